@@ -0,0 +1,86 @@
+package scryfall
+
+import (
+	"context"
+	"io"
+)
+
+type CardFetcher interface {
+	GetCard(ctx context.Context, id string) (Card, error)
+	GetCardByName(ctx context.Context, name string, exact bool, opts GetCardByNameOptions) (Card, error)
+	GetCardsByIdentifiers(ctx context.Context, identifiers []CardIdentifier) (GetCardsByIdentifiersResponse, error)
+	GetCardBySetCodeAndCollectorNumber(ctx context.Context, setCode string, collectorNumber string) (Card, error)
+	GetCardBySetCodeAndCollectorNumberInLang(ctx context.Context, setCode string, collectorNumber string, lang Lang) (Card, error)
+	GetCardByMultiverseID(ctx context.Context, multiverseID int) (Card, error)
+	GetCardByMTGOID(ctx context.Context, mtgoID int) (Card, error)
+	GetCardByArenaID(ctx context.Context, arenaID int) (Card, error)
+	GetCardByTCGPlayerID(ctx context.Context, tcgPlayerID int) (Card, error)
+	GetCardFromURI(ctx context.Context, uri string) (Card, error)
+}
+
+type CardSearcher interface {
+	SearchCards(ctx context.Context, query string, opts SearchCardsOptions) (CardListResponse, error)
+	SearchCardsAll(query string, opts SearchCardsOptions) (CardSearch, error)
+	AutocompleteCard(ctx context.Context, s string) ([]string, error)
+	GetRandomCard(ctx context.Context) (Card, error)
+}
+
+type SetFetcher interface {
+	ListSets(ctx context.Context) (List[Set], error)
+	GetSet(ctx context.Context, code string) (Set, error)
+}
+
+type RulingFetcher interface {
+	GetRulings(ctx context.Context, id string) (List[Ruling], error)
+	GetRulingsByMultiverseID(ctx context.Context, multiverseID int) (List[Ruling], error)
+	GetRulingsByMTGOID(ctx context.Context, mtgoID int) (List[Ruling], error)
+	GetRulingsByArenaID(ctx context.Context, arenaID int) (List[Ruling], error)
+	GetRulingsBySetCodeAndCollectorNumber(ctx context.Context, setCode string, collectorNumber int) (List[Ruling], error)
+}
+
+type CatalogFetcher interface {
+	GetCardNamesCatalog(ctx context.Context) (Catalog, error)
+	GetArtistNamesCatalog(ctx context.Context) (Catalog, error)
+	GetWordBankCatalog(ctx context.Context) (Catalog, error)
+	GetSuperTypesCatalog(ctx context.Context) (Catalog, error)
+	GetCardTypesCatalog(ctx context.Context) (Catalog, error)
+	GetCreatureTypesCatalog(ctx context.Context) (Catalog, error)
+	GetPlaneswalkerTypesCatalog(ctx context.Context) (Catalog, error)
+	GetLandTypesCatalog(ctx context.Context) (Catalog, error)
+	GetArtifactTypesCatalog(ctx context.Context) (Catalog, error)
+	GetBattleTypesCatalog(ctx context.Context) (Catalog, error)
+	GetEnchantmentTypesCatalog(ctx context.Context) (Catalog, error)
+	GetSpellTypesCatalog(ctx context.Context) (Catalog, error)
+	GetPowersCatalog(ctx context.Context) (Catalog, error)
+	GetToughnessesCatalog(ctx context.Context) (Catalog, error)
+	GetLoyaltiesCatalog(ctx context.Context) (Catalog, error)
+	GetKeywordAbilitiesCatalog(ctx context.Context) (Catalog, error)
+	GetKeywordActionsCatalog(ctx context.Context) (Catalog, error)
+	GetAbilityWordsCatalog(ctx context.Context) (Catalog, error)
+	GetFlavorWordsCatalog(ctx context.Context) (Catalog, error)
+	GetWatermarksCatalog(ctx context.Context) (Catalog, error)
+}
+
+type SymbolFetcher interface {
+	ListCardSymbols(ctx context.Context) (List[CardSymbol], error)
+	ParseManaCost(ctx context.Context, cost string) (ManaCost, error)
+}
+
+type BulkDataFetcher interface {
+	ListBulkData(ctx context.Context) (List[BulkData], error)
+	GetBulkDataByID(ctx context.Context, id string) (BulkData, error)
+	GetBulkDataByType(ctx context.Context, typ BulkDataType) (BulkData, error)
+	DownloadBulkData(ctx context.Context, bulkData BulkData, w io.Writer, options ...DownloadOption) (int64, error)
+}
+
+type API interface {
+	CardFetcher
+	CardSearcher
+	SetFetcher
+	RulingFetcher
+	CatalogFetcher
+	SymbolFetcher
+	BulkDataFetcher
+}
+
+var _ API = (*Client)(nil)
@@ -1,30 +1,48 @@
 package scryfall
 
 import (
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultMaxResumes = 5
+
+var (
+	ErrBulkDataSizeMismatch = errors.New("bulk data size mismatch")
+	ErrDownloadNotResumable = errors.New("bulk data download cannot be resumed")
+)
+
+type BulkDataType string
+
+const (
+	BulkDataTypeOracleCards   BulkDataType = "oracle_cards"
+	BulkDataTypeUniqueArtwork BulkDataType = "unique_artwork"
+	BulkDataTypeDefaultCards  BulkDataType = "default_cards"
+	BulkDataTypeAllCards      BulkDataType = "all_cards"
+	BulkDataTypeRulings       BulkDataType = "rulings"
 )
 
 type BulkData struct {
-	ID              string    `json:"id"`
-	Type            string    `json:"type"`
-	UpdatedAt       Timestamp `json:"updated_at"`
-	Name            string    `json:"name"`
-	URI             string    `json:"uri"`
-	Description     string    `json:"description"`
-	Size            int       `json:"size"`
-	DownloadURI     string    `json:"download_uri"`
-	ContentType     string    `json:"content_type"`
-	ContentEncoding string    `json:"content_encoding"`
-}
-
-func (c *Client) ListBulkData(ctx context.Context) ([]BulkData, error) {
-	bulkDataItems := []BulkData{}
-	err := c.listGet(ctx, "bulk-data", &bulkDataItems)
-	if err != nil {
-		return nil, err
-	}
-	return bulkDataItems, nil
+	ID              string       `json:"id"`
+	Type            BulkDataType `json:"type"`
+	UpdatedAt       Timestamp    `json:"updated_at"`
+	Name            string       `json:"name"`
+	URI             string       `json:"uri"`
+	Description     string       `json:"description"`
+	Size            int          `json:"size"`
+	DownloadURI     string       `json:"download_uri"`
+	ContentType     string       `json:"content_type"`
+	ContentEncoding string       `json:"content_encoding"`
+}
+
+func (c *Client) ListBulkData(ctx context.Context) (List[BulkData], error) {
+	return listGet[BulkData](ctx, c, "bulk-data")
 }
 
 func (c *Client) GetBulkDataByID(ctx context.Context, id string) (BulkData, error) {
@@ -37,7 +55,7 @@ func (c *Client) GetBulkDataByID(ctx context.Context, id string) (BulkData, erro
 	return bulkData, nil
 }
 
-func (c *Client) GetBulkDataByType(ctx context.Context, typ string) (BulkData, error) {
+func (c *Client) GetBulkDataByType(ctx context.Context, typ BulkDataType) (BulkData, error) {
 	bulkDataURI := fmt.Sprintf("bulk-data/%s", typ)
 	bulkData := BulkData{}
 	err := c.get(ctx, bulkDataURI, &bulkData)
@@ -46,3 +64,189 @@ func (c *Client) GetBulkDataByType(ctx context.Context, typ string) (BulkData, e
 	}
 	return bulkData, nil
 }
+
+type DownloadProgressFunc func(written int64, total int64)
+
+type downloadOptions struct {
+	progress   DownloadProgressFunc
+	maxResumes int
+}
+
+type DownloadOption func(*downloadOptions)
+
+func WithDownloadProgress(progress DownloadProgressFunc) DownloadOption {
+	return func(o *downloadOptions) {
+		o.progress = progress
+	}
+}
+
+func WithMaxResumes(maxResumes int) DownloadOption {
+	return func(o *downloadOptions) {
+		o.maxResumes = maxResumes
+	}
+}
+
+func (c *Client) DownloadBulkData(ctx context.Context, bulkData BulkData, w io.Writer, options ...DownloadOption) (int64, error) {
+	do := &downloadOptions{
+		maxResumes: defaultMaxResumes,
+	}
+	for _, option := range options {
+		option(do)
+	}
+	body := &resumableBody{
+		ctx:        ctx,
+		client:     c,
+		uri:        bulkData.DownloadURI,
+		maxResumes: do.maxResumes,
+	}
+	defer body.Close()
+	err := body.open()
+	if err != nil {
+		return 0, err
+	}
+	var r io.Reader = body
+	if body.contentEncoding == "gzip" {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return 0, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	pw := &progressWriter{
+		w:        w,
+		total:    int64(bulkData.Size),
+		progress: do.progress,
+	}
+	n, err := io.Copy(pw, r)
+	if err != nil {
+		return n, err
+	}
+	if bulkData.Size > 0 && n != int64(bulkData.Size) {
+		return n, fmt.Errorf("%w: expected %d bytes, got %d", ErrBulkDataSizeMismatch, bulkData.Size, n)
+	}
+	return n, nil
+}
+
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	progress DownloadProgressFunc
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	pw.written += int64(n)
+	if pw.progress != nil {
+		pw.progress(pw.written, pw.total)
+	}
+	return n, err
+}
+
+type resumableBody struct {
+	ctx             context.Context
+	client          *Client
+	uri             string
+	etag            string
+	contentEncoding string
+	offset          int64
+	resumes         int
+	maxResumes      int
+	body            io.ReadCloser
+}
+
+func (b *resumableBody) open() error {
+	req, err := http.NewRequestWithContext(b.ctx, http.MethodGet, b.uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", b.client.userAgent)
+	// Requesting gzip explicitly stops the transport from decoding the body,
+	// so offsets stay in terms of the bytes actually sent over the wire.
+	req.Header.Set("Accept-Encoding", "gzip")
+	if b.offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", b.offset))
+		if len(b.etag) != 0 {
+			req.Header.Set("If-Range", b.etag)
+		}
+	}
+	resp, err := b.client.downloadClient.Do(req)
+	if err != nil {
+		return err
+	}
+	switch {
+	case b.offset == 0 && resp.StatusCode == http.StatusOK:
+		b.etag = resp.Header.Get("ETag")
+		b.contentEncoding = resp.Header.Get("Content-Encoding")
+	case b.offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		// A range starting anywhere else would splice the wrong bytes into
+		// the gzip stream.
+		contentRange := resp.Header.Get("Content-Range")
+		start, ok := contentRangeStart(contentRange)
+		if !ok || start != b.offset {
+			resp.Body.Close()
+			return fmt.Errorf("%w: requested offset %d, got range %q", ErrDownloadNotResumable, b.offset, contentRange)
+		}
+	case b.offset > 0 && resp.StatusCode == http.StatusOK:
+		resp.Body.Close()
+		return ErrDownloadNotResumable
+	default:
+		defer resp.Body.Close()
+		return newResponseError(req, resp)
+	}
+	b.body = resp.Body
+	return nil
+}
+
+// contentRangeStart parses the first byte position of a "bytes start-end/size"
+// Content-Range header.
+func contentRangeStart(contentRange string) (int64, bool) {
+	byteRange, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(byteRange, "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+func (b *resumableBody) Read(p []byte) (int, error) {
+	for {
+		if b.body == nil {
+			err := b.open()
+			if err != nil {
+				return 0, err
+			}
+		}
+		n, err := b.body.Read(p)
+		b.offset += int64(n)
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		b.body.Close()
+		b.body = nil
+		if b.ctx.Err() != nil || b.resumes >= b.maxResumes {
+			return n, err
+		}
+		b.resumes++
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (b *resumableBody) Close() error {
+	if b.body == nil {
+		return nil
+	}
+	err := b.body.Close()
+	b.body = nil
+	return err
+}
@@ -0,0 +1,224 @@
+package bulk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sync"
+
+	"github.com/tencorvids/scryfall"
+)
+
+var ErrNotArray = errors.New("file is not a JSON array")
+
+// DecodeError reports the element that failed. Index is -1 when the file
+// itself is malformed. Offset is the input offset where decoding the element
+// started, which is just past the previous element and before any separator.
+type DecodeError struct {
+	Index  int
+	Offset int64
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("bulk: element %d at offset %d: %v", e.Index, e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+type decodeOptions struct {
+	workers int
+}
+
+type DecodeOption func(*decodeOptions)
+
+func WithWorkers(workers int) DecodeOption {
+	return func(o *decodeOptions) {
+		o.workers = workers
+	}
+}
+
+func Cards(r io.Reader, options ...DecodeOption) iter.Seq2[scryfall.Card, error] {
+	return Decode[scryfall.Card](r, options...)
+}
+
+func Rulings(r io.Reader, options ...DecodeOption) iter.Seq2[scryfall.Ruling, error] {
+	return Decode[scryfall.Ruling](r, options...)
+}
+
+func Each[T any](r io.Reader, fn func(T) error, options ...DecodeOption) error {
+	for v, err := range Decode[T](r, options...) {
+		if err != nil {
+			return err
+		}
+		err = fn(v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Decode[T any](r io.Reader, options ...DecodeOption) iter.Seq2[T, error] {
+	do := &decodeOptions{
+		workers: 1,
+	}
+	for _, option := range options {
+		option(do)
+	}
+	if do.workers > 1 {
+		return decodeParallel[T](r, do.workers)
+	}
+	return decodeSerial[T](r)
+}
+
+func openArray(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return &DecodeError{Index: -1, Offset: dec.InputOffset(), Err: err}
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return &DecodeError{Index: -1, Offset: dec.InputOffset(), Err: ErrNotArray}
+	}
+	return nil
+}
+
+func closeArray(dec *json.Decoder, index int) error {
+	_, err := dec.Token()
+	if err != nil {
+		return &DecodeError{Index: index, Offset: dec.InputOffset(), Err: err}
+	}
+	return nil
+}
+
+func decodeSerial[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		dec := json.NewDecoder(r)
+		err := openArray(dec)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		index := 0
+		for dec.More() {
+			offset := dec.InputOffset()
+			var v T
+			err := dec.Decode(&v)
+			if err != nil {
+				yield(zero, &DecodeError{Index: index, Offset: offset, Err: err})
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+			index++
+		}
+		err = closeArray(dec, index)
+		if err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+type decodeResult[T any] struct {
+	value T
+	err   error
+}
+
+type decodeJob[T any] struct {
+	index  int
+	offset int64
+	raw    json.RawMessage
+	result chan decodeResult[T]
+}
+
+func decodeParallel[T any](r io.Reader, workers int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		done := make(chan struct{})
+		jobs := make(chan decodeJob[T], workers)
+		order := make(chan decodeJob[T], workers*2)
+		// The producer reads from r, which belongs to the caller again once
+		// the iterator returns, so wait for every goroutine to exit first.
+		var wg sync.WaitGroup
+		defer wg.Wait()
+
+		wg.Add(workers + 1)
+		for range workers {
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					var v T
+					err := json.Unmarshal(job.raw, &v)
+					if err != nil {
+						err = &DecodeError{Index: job.index, Offset: job.offset, Err: err}
+					}
+					job.result <- decodeResult[T]{value: v, err: err}
+				}
+			}()
+		}
+
+		go func() {
+			defer wg.Done()
+			defer close(order)
+			defer close(jobs)
+			fail := func(err error) {
+				job := decodeJob[T]{result: make(chan decodeResult[T], 1)}
+				job.result <- decodeResult[T]{err: err}
+				select {
+				case order <- job:
+				case <-done:
+				}
+			}
+			dec := json.NewDecoder(r)
+			err := openArray(dec)
+			if err != nil {
+				fail(err)
+				return
+			}
+			index := 0
+			for dec.More() {
+				offset := dec.InputOffset()
+				var raw json.RawMessage
+				err := dec.Decode(&raw)
+				if err != nil {
+					fail(&DecodeError{Index: index, Offset: offset, Err: err})
+					return
+				}
+				job := decodeJob[T]{
+					index:  index,
+					offset: offset,
+					raw:    raw,
+					result: make(chan decodeResult[T], 1),
+				}
+				select {
+				case order <- job:
+				case <-done:
+					return
+				}
+				select {
+				case jobs <- job:
+				case <-done:
+					return
+				}
+				index++
+			}
+			err = closeArray(dec, index)
+			if err != nil {
+				fail(err)
+			}
+		}()
+
+		defer close(done)
+		for job := range order {
+			res := <-job.result
+			if !yield(res.value, res.err) || res.err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type element struct {
+	N int `json:"n"`
+}
+
+func elements(n int) string {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := range n {
+		if i != 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"n":%d}`, i)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func TestDecodeKeepsOrder(t *testing.T) {
+	for _, workers := range []int{1, 2, 8} {
+		next := 0
+		for v, err := range Decode[element](strings.NewReader(elements(500)), WithWorkers(workers)) {
+			if err != nil {
+				t.Fatalf("%d workers: %v", workers, err)
+			}
+			if v.N != next {
+				t.Fatalf("%d workers: got element %d, want %d", workers, v.N, next)
+			}
+			next++
+		}
+		if next != 500 {
+			t.Errorf("%d workers: got %d elements, want 500", workers, next)
+		}
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		index  int
+		offset int64
+		target error
+	}{
+		{`[{"n":0},{"n":"x"}]`, 1, 8, nil},
+		{`[{"n":0},{"n":}]`, 1, 8, nil},
+		{`[{"n":0}`, 1, 8, nil},
+		{`{"n":0}`, -1, 1, ErrNotArray},
+		{``, -1, 0, io.EOF},
+	} {
+		for _, workers := range []int{1, 4} {
+			count := 0
+			var err error
+			for _, err = range Decode[element](strings.NewReader(test.input), WithWorkers(workers)) {
+				if err != nil {
+					break
+				}
+				count++
+			}
+			decodeErr := &DecodeError{}
+			if !errors.As(err, &decodeErr) {
+				t.Errorf("%q, %d workers: got %v, want a DecodeError", test.input, workers, err)
+				continue
+			}
+			if decodeErr.Index != test.index || decodeErr.Offset != test.offset {
+				t.Errorf("%q, %d workers: got index %d offset %d, want %d and %d", test.input, workers, decodeErr.Index, decodeErr.Offset, test.index, test.offset)
+			}
+			if test.target != nil && !errors.Is(err, test.target) {
+				t.Errorf("%q, %d workers: got %v, want %v", test.input, workers, err, test.target)
+			}
+			if max(test.index, 0) != count {
+				t.Errorf("%q, %d workers: got %d elements before the error, want %d", test.input, workers, count, max(test.index, 0))
+			}
+		}
+	}
+}
+
+// countingReader counts reads without synchronization, so the race detector
+// flags any read made after the iterator has returned.
+type countingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.reads++
+	return c.r.Read(p)
+}
+
+func TestDecodeBreak(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		r := &countingReader{r: strings.NewReader(elements(100000))}
+		for v := range Decode[element](r, WithWorkers(workers)) {
+			if v.N == 2 {
+				break
+			}
+		}
+		reads := r.reads
+		time.Sleep(10 * time.Millisecond)
+		if r.reads != reads {
+			t.Errorf("%d workers: reader used after the iterator returned", workers)
+		}
+	}
+}
+
+func TestEach(t *testing.T) {
+	stop := errors.New("stop")
+	seen := 0
+	err := Each(strings.NewReader(elements(10)), func(v element) error {
+		seen++
+		if v.N == 3 {
+			return stop
+		}
+		return nil
+	}, WithWorkers(2))
+	if !errors.Is(err, stop) || seen != 4 {
+		t.Errorf("got %v after %d elements, want stop after 4", err, seen)
+	}
+}
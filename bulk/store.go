@@ -0,0 +1,374 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/tencorvids/scryfall"
+)
+
+var _ scryfall.CardFetcher = (*LocalStore)(nil)
+
+type setNumberKey struct {
+	set             string
+	collectorNumber string
+	lang            scryfall.Lang
+}
+
+type LocalStore struct {
+	cards          []scryfall.Card
+	byID           map[string]int
+	byOracleID     map[string][]int
+	byIllustration map[string][]int
+	byMultiverseID map[int]int
+	byMTGOID       map[int]int
+	byArenaID      map[int]int
+	byTCGPlayerID  map[int]int
+	bySetNumber    map[setNumberKey]int
+	byName         map[string][]int
+	byFuzzyName    map[string][]int
+}
+
+func NewLocalStore() *LocalStore {
+	return &LocalStore{
+		byID:           map[string]int{},
+		byOracleID:     map[string][]int{},
+		byIllustration: map[string][]int{},
+		byMultiverseID: map[int]int{},
+		byMTGOID:       map[int]int{},
+		byArenaID:      map[int]int{},
+		byTCGPlayerID:  map[int]int{},
+		bySetNumber:    map[setNumberKey]int{},
+		byName:         map[string][]int{},
+		byFuzzyName:    map[string][]int{},
+	}
+}
+
+func ReadLocalStore(r io.Reader, options ...DecodeOption) (*LocalStore, error) {
+	s := NewLocalStore()
+	err := Each(r, func(card scryfall.Card) error {
+		s.Add(card)
+		return nil
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func LoadLocalStore(path string, options ...DecodeOption) (*LocalStore, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadLocalStore(f, options...)
+}
+
+func (s *LocalStore) Add(card scryfall.Card) {
+	i := len(s.cards)
+	s.cards = append(s.cards, card)
+	s.byID[card.ID] = i
+	oracleIDs := map[string]bool{}
+	if len(card.OracleID) != 0 {
+		oracleIDs[card.OracleID] = true
+	}
+	for _, face := range card.CardFaces {
+		if face.OracleID != nil {
+			oracleIDs[*face.OracleID] = true
+		}
+	}
+	for oracleID := range oracleIDs {
+		s.byOracleID[oracleID] = append(s.byOracleID[oracleID], i)
+	}
+	illustrationIDs := map[string]bool{}
+	if card.IllustrationID != nil {
+		illustrationIDs[*card.IllustrationID] = true
+	}
+	for _, face := range card.CardFaces {
+		if face.IllustrationID != nil {
+			illustrationIDs[*face.IllustrationID] = true
+		}
+	}
+	for illustrationID := range illustrationIDs {
+		s.byIllustration[illustrationID] = append(s.byIllustration[illustrationID], i)
+	}
+	for _, multiverseID := range card.MultiverseIDs {
+		s.byMultiverseID[multiverseID] = i
+	}
+	if card.MTGOID != nil {
+		s.byMTGOID[*card.MTGOID] = i
+	}
+	if card.MTGOFoilID != nil {
+		s.byMTGOID[*card.MTGOFoilID] = i
+	}
+	if card.ArenaID != nil {
+		s.byArenaID[*card.ArenaID] = i
+	}
+	if card.TCGPlayerID != nil {
+		s.byTCGPlayerID[*card.TCGPlayerID] = i
+	}
+	if card.TCGPlayerEtchedID != nil {
+		s.byTCGPlayerID[*card.TCGPlayerEtchedID] = i
+	}
+	key := setNumberKey{
+		set:             strings.ToLower(card.Set),
+		collectorNumber: card.CollectorNumber,
+		lang:            card.Lang,
+	}
+	s.bySetNumber[key] = i
+	names := []string{card.Name}
+	for _, face := range card.CardFaces {
+		if face.Name != card.Name {
+			names = append(names, face.Name)
+		}
+	}
+	for _, name := range names {
+		s.byName[strings.ToLower(name)] = append(s.byName[strings.ToLower(name)], i)
+		s.byFuzzyName[fuzzyName(name)] = append(s.byFuzzyName[fuzzyName(name)], i)
+	}
+}
+
+func (s *LocalStore) Len() int {
+	return len(s.cards)
+}
+
+func (s *LocalStore) Cards() []scryfall.Card {
+	return s.cards
+}
+
+func notFound(details string) error {
+	return &scryfall.Error{
+		Status:  http.StatusNotFound,
+		Code:    "not_found",
+		Details: details,
+	}
+}
+
+func (s *LocalStore) lookup(m map[int]int, id int, kind string) (scryfall.Card, error) {
+	i, ok := m[id]
+	if !ok {
+		return scryfall.Card{}, notFound(fmt.Sprintf("No card found with the given %s ID.", kind))
+	}
+	return s.cards[i], nil
+}
+
+func (s *LocalStore) GetCard(ctx context.Context, id string) (scryfall.Card, error) {
+	i, ok := s.byID[id]
+	if !ok {
+		return scryfall.Card{}, notFound("No card found with the given ID.")
+	}
+	return s.cards[i], nil
+}
+
+func (s *LocalStore) GetCardsByOracleID(ctx context.Context, oracleID string) ([]scryfall.Card, error) {
+	indices, ok := s.byOracleID[oracleID]
+	if !ok {
+		return nil, notFound("No card found with the given oracle ID.")
+	}
+	cards := make([]scryfall.Card, 0, len(indices))
+	for _, i := range indices {
+		cards = append(cards, s.cards[i])
+	}
+	return cards, nil
+}
+
+func (s *LocalStore) GetCardByMultiverseID(ctx context.Context, multiverseID int) (scryfall.Card, error) {
+	return s.lookup(s.byMultiverseID, multiverseID, "Multiverse")
+}
+
+func (s *LocalStore) GetCardByMTGOID(ctx context.Context, mtgoID int) (scryfall.Card, error) {
+	return s.lookup(s.byMTGOID, mtgoID, "MTGO")
+}
+
+func (s *LocalStore) GetCardByArenaID(ctx context.Context, arenaID int) (scryfall.Card, error) {
+	return s.lookup(s.byArenaID, arenaID, "Arena")
+}
+
+func (s *LocalStore) GetCardByTCGPlayerID(ctx context.Context, tcgPlayerID int) (scryfall.Card, error) {
+	return s.lookup(s.byTCGPlayerID, tcgPlayerID, "TCGplayer")
+}
+
+func (s *LocalStore) GetCardBySetCodeAndCollectorNumber(ctx context.Context, setCode string, collectorNumber string) (scryfall.Card, error) {
+	return s.GetCardBySetCodeAndCollectorNumberInLang(ctx, setCode, collectorNumber, scryfall.LangEnglish)
+}
+
+func (s *LocalStore) GetCardBySetCodeAndCollectorNumberInLang(ctx context.Context, setCode string, collectorNumber string, lang scryfall.Lang) (scryfall.Card, error) {
+	key := setNumberKey{
+		set:             strings.ToLower(setCode),
+		collectorNumber: collectorNumber,
+		lang:            lang,
+	}
+	i, ok := s.bySetNumber[key]
+	if !ok {
+		return scryfall.Card{}, notFound("No card found with the given set code and collector number.")
+	}
+	return s.cards[i], nil
+}
+
+func (s *LocalStore) GetCardFromURI(ctx context.Context, uri string) (scryfall.Card, error) {
+	parts := strings.Split(uri, "/")
+	if len(parts) < 6 {
+		return scryfall.Card{}, fmt.Errorf("invalid card url format")
+	}
+	setCode := parts[len(parts)-3]
+	collectorNumber := parts[len(parts)-2]
+	return s.GetCardBySetCodeAndCollectorNumber(ctx, setCode, collectorNumber)
+}
+
+func (s *LocalStore) GetCardByName(ctx context.Context, name string, exact bool, opts scryfall.GetCardByNameOptions) (scryfall.Card, error) {
+	indices := s.byName[strings.ToLower(name)]
+	if len(indices) == 0 && !exact {
+		indices = s.fuzzyLookup(name)
+	}
+	indices = s.filterSet(indices, opts.Set)
+	if len(indices) == 0 {
+		return scryfall.Card{}, notFound("No cards found matching the given name.")
+	}
+	oracleIDs := map[string]bool{}
+	for _, i := range indices {
+		oracleIDs[s.cards[i].OracleID] = true
+	}
+	if len(oracleIDs) > 1 {
+		ambiguous := "ambiguous"
+		return scryfall.Card{}, &scryfall.Error{
+			Status:  http.StatusNotFound,
+			Code:    "not_found",
+			Details: "Too many cards match ambiguous name. Add more words to refine your search.",
+			Type:    &ambiguous,
+		}
+	}
+	return s.cards[s.preferred(indices)], nil
+}
+
+func (s *LocalStore) GetCardsByIdentifiers(ctx context.Context, identifiers []scryfall.CardIdentifier) (scryfall.GetCardsByIdentifiersResponse, error) {
+	response := scryfall.GetCardsByIdentifiersResponse{
+		NotFound:        []scryfall.CardIdentifier{},
+		Data:            []scryfall.Card{},
+		NotFoundIndices: []int{},
+		DataIndices:     []int{},
+	}
+	for i, identifier := range identifiers {
+		err := identifier.Validate()
+		if err != nil {
+			return scryfall.GetCardsByIdentifiersResponse{}, fmt.Errorf("identifier %d: %w", i, err)
+		}
+	}
+	for i, identifier := range identifiers {
+		card, err := s.getCardByIdentifier(ctx, identifier)
+		if err != nil {
+			response.NotFound = append(response.NotFound, identifier)
+			response.NotFoundIndices = append(response.NotFoundIndices, i)
+			continue
+		}
+		response.Data = append(response.Data, card)
+		response.DataIndices = append(response.DataIndices, i)
+	}
+	return response, nil
+}
+
+func (s *LocalStore) getCardByIdentifier(ctx context.Context, identifier scryfall.CardIdentifier) (scryfall.Card, error) {
+	switch {
+	case len(identifier.ID) != 0:
+		return s.GetCard(ctx, identifier.ID)
+	case identifier.MTGOID != 0:
+		return s.GetCardByMTGOID(ctx, identifier.MTGOID)
+	case identifier.MultiverseID != 0:
+		return s.GetCardByMultiverseID(ctx, identifier.MultiverseID)
+	case len(identifier.OracleID) != 0:
+		return s.first(s.byOracleID[identifier.OracleID], "No card found with the given oracle ID.")
+	case len(identifier.IllustrationID) != 0:
+		return s.first(s.byIllustration[identifier.IllustrationID], "No card found with the given illustration ID.")
+	case len(identifier.CollectorNumber) != 0:
+		return s.GetCardBySetCodeAndCollectorNumber(ctx, identifier.Set, identifier.CollectorNumber)
+	}
+	opts := scryfall.GetCardByNameOptions{
+		Set: identifier.Set,
+	}
+	return s.GetCardByName(ctx, identifier.Name, true, opts)
+}
+
+func (s *LocalStore) first(indices []int, details string) (scryfall.Card, error) {
+	if len(indices) == 0 {
+		return scryfall.Card{}, notFound(details)
+	}
+	return s.cards[s.preferred(indices)], nil
+}
+
+func (s *LocalStore) fuzzyLookup(name string) []int {
+	key := fuzzyName(name)
+	if indices, ok := s.byFuzzyName[key]; ok {
+		return indices
+	}
+	var prefixMatches []int
+	for candidate, indices := range s.byFuzzyName {
+		if strings.HasPrefix(candidate, key) {
+			prefixMatches = append(prefixMatches, indices...)
+		}
+	}
+	if len(prefixMatches) != 0 {
+		return sortedIndices(prefixMatches)
+	}
+	var containsMatches []int
+	for candidate, indices := range s.byFuzzyName {
+		if strings.Contains(candidate, key) {
+			containsMatches = append(containsMatches, indices...)
+		}
+	}
+	return sortedIndices(containsMatches)
+}
+
+// sortedIndices puts candidates gathered from a map back into insertion
+// order, so that preferred breaks ties the same way on every call.
+func sortedIndices(indices []int) []int {
+	slices.Sort(indices)
+	return slices.Compact(indices)
+}
+
+func (s *LocalStore) filterSet(indices []int, set string) []int {
+	if len(set) == 0 {
+		return indices
+	}
+	filtered := []int{}
+	for _, i := range indices {
+		if strings.EqualFold(s.cards[i].Set, set) {
+			filtered = append(filtered, i)
+		}
+	}
+	return filtered
+}
+
+// preferred mirrors the API's choice for named lookups: an English printing
+// when one exists, otherwise the most recent release.
+func (s *LocalStore) preferred(indices []int) int {
+	best := indices[0]
+	for _, i := range indices[1:] {
+		card, bestCard := s.cards[i], s.cards[best]
+		if (card.Lang == scryfall.LangEnglish) != (bestCard.Lang == scryfall.LangEnglish) {
+			if card.Lang == scryfall.LangEnglish {
+				best = i
+			}
+			continue
+		}
+		if card.ReleasedAt.After(bestCard.ReleasedAt.Time) {
+			best = i
+		}
+	}
+	return best
+}
+
+func fuzzyName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,165 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tencorvids/scryfall"
+)
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func released(s string) scryfall.Date {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return scryfall.Date{Time: t}
+}
+
+func testStore() *LocalStore {
+	store := NewLocalStore()
+	for _, card := range []scryfall.Card{
+		{ID: "bolt-lea", OracleID: "bolt", Name: "Lightning Bolt", Set: "lea", CollectorNumber: "161", Lang: scryfall.LangEnglish, ReleasedAt: released("1993-08-05"), MultiverseIDs: []int{209}},
+		{ID: "bolt-m10", OracleID: "bolt", Name: "Lightning Bolt", Set: "m10", CollectorNumber: "146", Lang: scryfall.LangEnglish, ReleasedAt: released("2009-07-17"), MTGOID: intPtr(33328), MTGOFoilID: intPtr(33329), TCGPlayerID: intPtr(1234)},
+		{ID: "bolt-m10-ja", OracleID: "bolt", Name: "Lightning Bolt", Set: "m10", CollectorNumber: "146", Lang: scryfall.LangJapanese, ReleasedAt: released("2009-07-17")},
+		{ID: "bolt-2xm", OracleID: "bolt", Name: "Lightning Bolt", Set: "2xm", CollectorNumber: "129", Lang: scryfall.LangEnglish, ReleasedAt: released("2020-08-07"), ArenaID: intPtr(75000)},
+		{ID: "bolt-sld-ja", OracleID: "bolt", Name: "Lightning Bolt", Set: "sld", CollectorNumber: "1", Lang: scryfall.LangJapanese, ReleasedAt: released("2024-01-01")},
+		{ID: "growth", OracleID: "growth", Name: "Giant Growth", Set: "lea", CollectorNumber: "198", Lang: scryfall.LangEnglish, ReleasedAt: released("1993-08-05")},
+		{ID: "spider", OracleID: "spider", Name: "Giant Spider", Set: "lea", CollectorNumber: "199", Lang: scryfall.LangEnglish, ReleasedAt: released("1993-08-05")},
+		{ID: "fire-ice-apc", OracleID: "fire-ice", Name: "Fire // Ice", Set: "apc", CollectorNumber: "128", Lang: scryfall.LangEnglish, ReleasedAt: released("2001-06-04"), CardFaces: []scryfall.CardFace{{Name: "Fire"}, {Name: "Ice"}}},
+		{ID: "fire-ice-cmr", OracleID: "fire-ice", Name: "Fire // Ice", Set: "cmr", CollectorNumber: "290", Lang: scryfall.LangEnglish, ReleasedAt: released("2001-06-04"), CardFaces: []scryfall.CardFace{{Name: "Fire"}, {Name: "Ice"}}},
+	} {
+		store.Add(card)
+	}
+	return store
+}
+
+func TestLocalStoreLookups(t *testing.T) {
+	store := testStore()
+	ctx := context.Background()
+	for _, test := range []struct {
+		name   string
+		lookup func() (scryfall.Card, error)
+		want   string
+	}{
+		{"id", func() (scryfall.Card, error) { return store.GetCard(ctx, "bolt-2xm") }, "bolt-2xm"},
+		{"multiverse", func() (scryfall.Card, error) { return store.GetCardByMultiverseID(ctx, 209) }, "bolt-lea"},
+		{"mtgo", func() (scryfall.Card, error) { return store.GetCardByMTGOID(ctx, 33328) }, "bolt-m10"},
+		{"mtgo foil", func() (scryfall.Card, error) { return store.GetCardByMTGOID(ctx, 33329) }, "bolt-m10"},
+		{"arena", func() (scryfall.Card, error) { return store.GetCardByArenaID(ctx, 75000) }, "bolt-2xm"},
+		{"tcgplayer", func() (scryfall.Card, error) { return store.GetCardByTCGPlayerID(ctx, 1234) }, "bolt-m10"},
+		{"set and number", func() (scryfall.Card, error) { return store.GetCardBySetCodeAndCollectorNumber(ctx, "M10", "146") }, "bolt-m10"},
+		{"set, number and lang", func() (scryfall.Card, error) {
+			return store.GetCardBySetCodeAndCollectorNumberInLang(ctx, "m10", "146", scryfall.LangJapanese)
+		}, "bolt-m10-ja"},
+		{"uri", func() (scryfall.Card, error) {
+			return store.GetCardFromURI(ctx, "https://scryfall.com/card/2xm/129/lightning-bolt")
+		}, "bolt-2xm"},
+	} {
+		card, err := test.lookup()
+		if err != nil || card.ID != test.want {
+			t.Errorf("%s: got %q, %v, want %q", test.name, card.ID, err, test.want)
+		}
+	}
+}
+
+func TestLocalStoreNotFound(t *testing.T) {
+	store := testStore()
+	ctx := context.Background()
+	for name, lookup := range map[string]func() (scryfall.Card, error){
+		"id":    func() (scryfall.Card, error) { return store.GetCard(ctx, "missing") },
+		"arena": func() (scryfall.Card, error) { return store.GetCardByArenaID(ctx, 1) },
+		"lang": func() (scryfall.Card, error) {
+			return store.GetCardBySetCodeAndCollectorNumberInLang(ctx, "lea", "161", scryfall.LangJapanese)
+		},
+		"exact": func() (scryfall.Card, error) {
+			return store.GetCardByName(ctx, "lightning", true, scryfall.GetCardByNameOptions{})
+		},
+		"wrong set": func() (scryfall.Card, error) {
+			return store.GetCardByName(ctx, "Giant Growth", true, scryfall.GetCardByNameOptions{Set: "m10"})
+		},
+	} {
+		_, err := lookup()
+		if !errors.Is(err, scryfall.ErrNotFound) {
+			t.Errorf("%s: got %v, want ErrNotFound", name, err)
+		}
+	}
+}
+
+func TestLocalStorePreferred(t *testing.T) {
+	store := testStore()
+	ctx := context.Background()
+	for _, test := range []struct {
+		name  string
+		exact bool
+		opts  scryfall.GetCardByNameOptions
+		want  string
+	}{
+		{"lightning bolt", true, scryfall.GetCardByNameOptions{}, "bolt-2xm"},
+		{"LIGHTNING BOLT", true, scryfall.GetCardByNameOptions{Set: "m10"}, "bolt-m10"},
+		{"Lightning Bolt", true, scryfall.GetCardByNameOptions{Set: "sld"}, "bolt-sld-ja"},
+		{"Fire", true, scryfall.GetCardByNameOptions{}, "fire-ice-apc"},
+		{"lightnin", false, scryfall.GetCardByNameOptions{}, "bolt-2xm"},
+		{"ightning", false, scryfall.GetCardByNameOptions{}, "bolt-2xm"},
+		{"fire ice", false, scryfall.GetCardByNameOptions{}, "fire-ice-apc"},
+		{"fi", false, scryfall.GetCardByNameOptions{}, "fire-ice-apc"},
+	} {
+		for range 10 {
+			card, err := store.GetCardByName(ctx, test.name, test.exact, test.opts)
+			if err != nil || card.ID != test.want {
+				t.Errorf("%q (exact %t, set %q): got %q, %v, want %q", test.name, test.exact, test.opts.Set, card.ID, err, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestLocalStoreAmbiguousName(t *testing.T) {
+	store := testStore()
+	_, err := store.GetCardByName(context.Background(), "giant", false, scryfall.GetCardByNameOptions{})
+	if !errors.Is(err, scryfall.ErrAmbiguousName) {
+		t.Errorf("got %v, want ErrAmbiguousName", err)
+	}
+	card, err := store.GetCardByName(context.Background(), "giant sp", false, scryfall.GetCardByNameOptions{})
+	if err != nil || card.ID != "spider" {
+		t.Errorf("got %q, %v, want spider", card.ID, err)
+	}
+}
+
+func TestLocalStoreIdentifiers(t *testing.T) {
+	store := testStore()
+	response, err := store.GetCardsByIdentifiers(context.Background(), []scryfall.CardIdentifier{
+		scryfall.NewCardIdentifierByOracleID("bolt"),
+		scryfall.NewCardIdentifierByName("Nope"),
+		scryfall.NewCardIdentifierBySetAndCollectorNumber("lea", "198"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, card := range response.Data {
+		ids = append(ids, card.ID)
+	}
+	if got := strings.Join(ids, ","); got != "bolt-2xm,growth" {
+		t.Errorf("got %s, want bolt-2xm,growth", got)
+	}
+	if len(response.NotFoundIndices) != 1 || response.NotFoundIndices[0] != 1 {
+		t.Errorf("got not found indices %v, want [1]", response.NotFoundIndices)
+	}
+}
+
+func TestReadLocalStore(t *testing.T) {
+	store, err := ReadLocalStore(strings.NewReader(`[{"id":"a","name":"A"},{"id":"b","name":"B"}]`), WithWorkers(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != 2 || store.Cards()[1].ID != "b" {
+		t.Errorf("got %d cards, want a then b", store.Len())
+	}
+}
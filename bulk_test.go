@@ -0,0 +1,219 @@
+package scryfall
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDownloadBulkDataOutlivesClientTimeout(t *testing.T) {
+	chunks := [][]byte{[]byte(`[{"object":"card"},`), []byte(`{"object":"card"}]`)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, chunk := range chunks {
+			w.Write(chunk)
+			w.(http.Flusher).Flush()
+			time.Sleep(150 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithHTTPClient(&http.Client{
+		Timeout:   100 * time.Millisecond,
+		Transport: server.Client().Transport,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	bulkData := BulkData{DownloadURI: server.URL, Size: len(bytes.Join(chunks, nil))}
+	n, err := client.DownloadBulkData(context.Background(), bulkData, &b, WithMaxResumes(0))
+	if err != nil {
+		t.Fatalf("DownloadBulkData: %v", err)
+	}
+	if n != int64(bulkData.Size) || b.String() != string(bytes.Join(chunks, nil)) {
+		t.Errorf("downloaded %d bytes %q", n, b.String())
+	}
+}
+
+func TestDownloadBulkDataContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("["))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var b bytes.Buffer
+	_, err = client.DownloadBulkData(ctx, BulkData{DownloadURI: server.URL}, &b)
+	if err == nil {
+		t.Fatal("DownloadBulkData succeeded, want a context error")
+	}
+}
+
+const bulkETag = `"bulk-v1"`
+
+type rangeServerOptions struct {
+	// dropAt cuts the first response off after this many bytes.
+	dropAt int
+	// ignoreRange answers range requests with the whole file.
+	ignoreRange bool
+	// rangeShift moves the start reported in Content-Range.
+	rangeShift int
+}
+
+// rangeServer serves payload gzip-encoded, honoring Range requests that
+// carry the matching If-Range. It records the Range header of each request.
+func rangeServer(t *testing.T, payload []byte, options rangeServerOptions) (*httptest.Server, *[]string) {
+	t.Helper()
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(payload)
+	gz.Close()
+	body := compressed.Bytes()
+
+	var mu sync.Mutex
+	ranges := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		first := len(ranges) == 1
+		mu.Unlock()
+		w.Header().Set("ETag", bulkETag)
+		w.Header().Set("Content-Encoding", "gzip")
+		start := 0
+		byteRange, ok := strings.CutPrefix(r.Header.Get("Range"), "bytes=")
+		if ok && !options.ignoreRange && r.Header.Get("If-Range") == bulkETag {
+			fmt.Sscanf(byteRange, "%d-", &start)
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start+options.rangeShift, len(body)-1, len(body)))
+			w.Header().Set("Content-Length", fmt.Sprint(len(body)-start))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(body[start:])
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		if first && options.dropAt > 0 {
+			w.Write(body[:options.dropAt])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		w.Write(body)
+	}))
+	return server, &ranges
+}
+
+func bulkPayload() []byte {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := range 2000 {
+		if i != 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"object":"card","name":"Card %d"}`, i)
+	}
+	b.WriteString("]")
+	return []byte(b.String())
+}
+
+func TestDownloadBulkDataGzip(t *testing.T) {
+	payload := bulkPayload()
+	server, _ := rangeServer(t, payload, rangeServerOptions{})
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	var b bytes.Buffer
+	n, err := client.DownloadBulkData(context.Background(), BulkData{DownloadURI: server.URL, Size: len(payload)}, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(payload)) || !bytes.Equal(b.Bytes(), payload) {
+		t.Errorf("downloaded %d bytes, want the %d byte payload", n, len(payload))
+	}
+}
+
+func TestDownloadBulkDataResumes(t *testing.T) {
+	payload := bulkPayload()
+	server, ranges := rangeServer(t, payload, rangeServerOptions{dropAt: 100})
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	var b bytes.Buffer
+	n, err := client.DownloadBulkData(context.Background(), BulkData{DownloadURI: server.URL, Size: len(payload)}, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(payload)) || !bytes.Equal(b.Bytes(), payload) {
+		t.Errorf("downloaded %d bytes, want the %d byte payload", n, len(payload))
+	}
+	if len(*ranges) != 2 || (*ranges)[1] != "bytes=100-" {
+		t.Errorf("got ranges %q, want a second request for bytes=100-", *ranges)
+	}
+}
+
+func TestDownloadBulkDataNotResumable(t *testing.T) {
+	for name, options := range map[string]rangeServerOptions{
+		"range ignored":       {dropAt: 100, ignoreRange: true},
+		"wrong content range": {dropAt: 100, rangeShift: -10},
+	} {
+		payload := bulkPayload()
+		server, _ := rangeServer(t, payload, options)
+		client := newTestClient(t, server)
+
+		var b bytes.Buffer
+		_, err := client.DownloadBulkData(context.Background(), BulkData{DownloadURI: server.URL, Size: len(payload)}, &b)
+		if !errors.Is(err, ErrDownloadNotResumable) {
+			t.Errorf("%s: got %v, want ErrDownloadNotResumable", name, err)
+		}
+		server.Close()
+	}
+}
+
+func TestDownloadBulkDataSizeMismatch(t *testing.T) {
+	payload := bulkPayload()
+	server, _ := rangeServer(t, payload, rangeServerOptions{})
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	var b bytes.Buffer
+	_, err := client.DownloadBulkData(context.Background(), BulkData{DownloadURI: server.URL, Size: len(payload) + 1}, &b)
+	if !errors.Is(err, ErrBulkDataSizeMismatch) {
+		t.Errorf("got %v, want ErrBulkDataSizeMismatch", err)
+	}
+}
+
+func TestDownloadBulkDataProgress(t *testing.T) {
+	payload := bulkPayload()
+	server, _ := rangeServer(t, payload, rangeServerOptions{dropAt: 100})
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	var last, calls int64
+	progress := func(written int64, total int64) {
+		if written < last || total != int64(len(payload)) {
+			t.Errorf("progress(%d, %d) after %d", written, total, last)
+		}
+		last = written
+		calls++
+	}
+	var b bytes.Buffer
+	_, err := client.DownloadBulkData(context.Background(), BulkData{DownloadURI: server.URL, Size: len(payload)}, &b, WithDownloadProgress(progress))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls == 0 || last != int64(len(payload)) {
+		t.Errorf("progress ended at %d after %d calls, want %d", last, calls, len(payload))
+	}
+}
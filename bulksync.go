@@ -0,0 +1,151 @@
+package scryfall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const bulkSyncStateFile = "state.json"
+
+type BulkSyncResult struct {
+	Type              BulkDataType
+	Path              string
+	PreviousUpdatedAt time.Time
+	UpdatedAt         time.Time
+	Size              int64
+	Downloaded        bool
+}
+
+type BulkSyncSummary struct {
+	Results []BulkSyncResult
+}
+
+func (s BulkSyncSummary) Downloaded() []BulkSyncResult {
+	downloaded := []BulkSyncResult{}
+	for _, result := range s.Results {
+		if result.Downloaded {
+			downloaded = append(downloaded, result)
+		}
+	}
+	return downloaded
+}
+
+type BulkSync struct {
+	client BulkDataFetcher
+	dir    string
+	types  []BulkDataType
+}
+
+func NewBulkSync(client BulkDataFetcher, dir string, types ...BulkDataType) *BulkSync {
+	return &BulkSync{
+		client: client,
+		dir:    dir,
+		types:  types,
+	}
+}
+
+func (s *BulkSync) Path(typ BulkDataType) string {
+	return filepath.Join(s.dir, string(typ)+".json")
+}
+
+func (s *BulkSync) Sync(ctx context.Context, options ...DownloadOption) (BulkSyncSummary, error) {
+	err := os.MkdirAll(s.dir, 0o755)
+	if err != nil {
+		return BulkSyncSummary{}, err
+	}
+	state, err := s.readState()
+	if err != nil {
+		return BulkSyncSummary{}, err
+	}
+	list, err := s.client.ListBulkData(ctx)
+	if err != nil {
+		return BulkSyncSummary{}, err
+	}
+	summary := BulkSyncSummary{}
+	for _, bulkData := range list.Data {
+		if len(s.types) != 0 && !slices.Contains(s.types, bulkData.Type) {
+			continue
+		}
+		result := BulkSyncResult{
+			Type:              bulkData.Type,
+			Path:              s.Path(bulkData.Type),
+			PreviousUpdatedAt: state[bulkData.Type],
+			UpdatedAt:         bulkData.UpdatedAt.Time,
+		}
+		info, err := os.Stat(result.Path)
+		if err == nil && !bulkData.UpdatedAt.After(result.PreviousUpdatedAt) {
+			result.Size = info.Size()
+			summary.Results = append(summary.Results, result)
+			continue
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return summary, err
+		}
+		result.Size, err = s.download(ctx, bulkData, result.Path, options...)
+		if err != nil {
+			return summary, err
+		}
+		result.Downloaded = true
+		state[bulkData.Type] = bulkData.UpdatedAt.Time
+		err = s.writeState(state)
+		if err != nil {
+			return summary, err
+		}
+		summary.Results = append(summary.Results, result)
+	}
+	return summary, nil
+}
+
+func (s *BulkSync) download(ctx context.Context, bulkData BulkData, path string, options ...DownloadOption) (int64, error) {
+	f, err := os.CreateTemp(s.dir, string(bulkData.Type)+"-*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(f.Name())
+	n, err := s.client.DownloadBulkData(ctx, bulkData, f, options...)
+	if err != nil {
+		f.Close()
+		return n, err
+	}
+	err = f.Close()
+	if err != nil {
+		return n, err
+	}
+	return n, os.Rename(f.Name(), path)
+}
+
+func (s *BulkSync) readState() (map[BulkDataType]time.Time, error) {
+	state := map[BulkDataType]time.Time{}
+	b, err := os.ReadFile(filepath.Join(s.dir, bulkSyncStateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &state)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *BulkSync) writeState(state map[BulkDataType]time.Time) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, bulkSyncStateFile)
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, b, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
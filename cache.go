@@ -0,0 +1,279 @@
+package scryfall
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMemoryCacheCapacity  = 1024
+	defaultStaleWhileRevalidate = time.Hour
+)
+
+type CacheEntry struct {
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+	Delete(key string)
+}
+
+// defaultCacheTTLs is keyed by path prefix relative to the base URI. Cards
+// carry prices, which Scryfall refreshes several times a day, so they expire
+// much sooner than reference data.
+func defaultCacheTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		"catalog/":           24 * time.Hour,
+		"sets":               24 * time.Hour,
+		"symbology":          24 * time.Hour,
+		"bulk-data":          time.Hour,
+		"cards/":             10 * time.Minute,
+		"cards/autocomplete": time.Hour,
+		"cards/random":       0,
+	}
+}
+
+func (c *Client) relativePath(uri *url.URL) string {
+	path := strings.TrimPrefix(uri.Path, c.baseURI.Path)
+	return strings.TrimPrefix(path, "/")
+}
+
+func (c *Client) cacheTTL(uri *url.URL) time.Duration {
+	path := c.relativePath(uri)
+	var ttl time.Duration
+	longest := -1
+	for prefix, prefixTTL := range c.cacheTTLs {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			ttl = prefixTTL
+			longest = len(prefix)
+		}
+	}
+	return ttl
+}
+
+// cachedReq serves fresh entries from the cache. An entry that expired less
+// than staleWhileRevalidate ago is served as is and refreshed in the
+// background; anything older is revalidated first, falling back to the stale
+// entry only when the API is unavailable.
+func (c *Client) cachedReq(ctx context.Context, req *http.Request, respBody interface{}) error {
+	ttl := c.cacheTTL(req.URL)
+	if ttl <= 0 {
+		resp, err := c.doShared(ctx, req)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(resp.body, respBody)
+	}
+	key := req.URL.String()
+	now := time.Now()
+	entry, cached := c.cache.Get(key)
+	if cached && now.Before(entry.ExpiresAt) {
+		return json.Unmarshal(entry.Body, respBody)
+	}
+	if cached && now.Before(entry.ExpiresAt.Add(c.staleWhileRevalidate)) {
+		c.revalidateInBackground(ctx, req, key, entry, ttl)
+		return json.Unmarshal(entry.Body, respBody)
+	}
+	body, err := c.revalidate(ctx, req, key, entry, cached, ttl)
+	if err != nil {
+		if cached && isUnavailable(ctx, err) {
+			return json.Unmarshal(entry.Body, respBody)
+		}
+		return err
+	}
+	return json.Unmarshal(body, respBody)
+}
+
+// revalidate fetches key, conditionally when there is a cached entry, and
+// stores the result. Bodies that are not valid JSON are returned but never
+// cached.
+func (c *Client) revalidate(ctx context.Context, req *http.Request, key string, entry CacheEntry, cached bool, ttl time.Duration) ([]byte, error) {
+	if cached {
+		if len(entry.ETag) != 0 {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if len(entry.LastModified) != 0 {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+	resp, err := c.doShared(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if resp.status == http.StatusNotModified && cached {
+		entry.StoredAt = now
+		entry.ExpiresAt = now.Add(ttl)
+		c.cache.Set(key, entry)
+		return entry.Body, nil
+	}
+	if json.Valid(resp.body) {
+		c.cache.Set(key, CacheEntry{
+			Body:         resp.body,
+			ETag:         resp.header.Get("ETag"),
+			LastModified: resp.header.Get("Last-Modified"),
+			StoredAt:     now,
+			ExpiresAt:    now.Add(ttl),
+		})
+	}
+	return resp.body, nil
+}
+
+// revalidateInBackground refreshes key at most once at a time. The refresh
+// outlives the caller's context, and its errors are dropped: the stale entry
+// stays in place until a later request manages to refresh it.
+func (c *Client) revalidateInBackground(ctx context.Context, req *http.Request, key string, entry CacheEntry, ttl time.Duration) {
+	_, running := c.revalidating.LoadOrStore(key, struct{}{})
+	if running {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	req = req.Clone(ctx)
+	go func() {
+		defer c.revalidating.Delete(key)
+		c.revalidate(ctx, req, key, entry, true, ttl)
+	}()
+}
+
+func isUnavailable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	scryfallErr := &Error{}
+	if errors.As(err, &scryfallErr) {
+		return scryfallErr.Status == http.StatusTooManyRequests || scryfallErr.Status >= http.StatusInternalServerError
+	}
+	return true
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity <= 0 {
+		capacity = defaultMemoryCacheCapacity
+	}
+	return &MemoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+func (m *MemoryCache) Get(key string) (CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	elem, ok := m.items[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	m.order.MoveToFront(elem)
+	return elem.Value.(*memoryCacheItem).entry, true
+}
+
+func (m *MemoryCache) Set(key string, entry CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if elem, ok := m.items[key]; ok {
+		elem.Value.(*memoryCacheItem).entry = entry
+		m.order.MoveToFront(elem)
+		return
+	}
+	m.items[key] = m.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+	for m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if elem, ok := m.items[key]; ok {
+		m.order.Remove(elem)
+		delete(m.items, key)
+	}
+}
+
+type DiskCache struct {
+	dir string
+}
+
+func NewDiskCache(dir string) (*DiskCache, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (d *DiskCache) Get(key string) (CacheEntry, bool) {
+	b, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	entry := CacheEntry{}
+	err = json.Unmarshal(b, &entry)
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Set is best effort: a failed write only costs a future cache miss.
+func (d *DiskCache) Set(key string, entry CacheEntry) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	f, err := os.CreateTemp(d.dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(b)
+	closeErr := f.Close()
+	if err != nil || closeErr != nil {
+		os.Remove(f.Name())
+		return
+	}
+	err = os.Rename(f.Name(), d.path(key))
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+func (d *DiskCache) Delete(key string) {
+	os.Remove(d.path(key))
+}
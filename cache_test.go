@@ -0,0 +1,224 @@
+package scryfall
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	staleCard = `{"object":"card","name":"Stale Bolt"}`
+	freshCard = `{"object":"card","name":"Fresh Bolt"}`
+)
+
+// etagServer answers with freshCard and ETag "v2", or 304 when the request
+// already carries that tag. status, when set, overrides both.
+func etagServer(status *atomic.Int32) (*httptest.Server, *atomic.Int32) {
+	requests := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if code := int(status.Load()); code != 0 {
+			w.WriteHeader(code)
+			fmt.Fprintf(w, `{"object":"error","status":%d,"code":"unavailable","details":"down"}`, code)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v2"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v2"`)
+		w.Write([]byte(freshCard))
+	}))
+	return server, requests
+}
+
+func seedCache(server *httptest.Server, path string, entry CacheEntry) *MemoryCache {
+	cache := NewMemoryCache(0)
+	cache.Set(server.URL+path, entry)
+	return cache
+}
+
+func TestCacheServesFreshEntries(t *testing.T) {
+	server, requests := etagServer(&atomic.Int32{})
+	defer server.Close()
+	cache := seedCache(server, "/cards/bolt", CacheEntry{
+		Body:      []byte(staleCard),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	client := newTestClient(t, server, WithCache(cache))
+
+	card, err := client.GetCard(context.Background(), "bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Name != "Stale Bolt" || requests.Load() != 0 {
+		t.Errorf("got %q after %d requests, want the cached card without a request", card.Name, requests.Load())
+	}
+}
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	server, requests := etagServer(&atomic.Int32{})
+	defer server.Close()
+	cache := seedCache(server, "/cards/bolt", CacheEntry{
+		Body:      []byte(staleCard),
+		ETag:      `"v2"`,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	client := newTestClient(t, server, WithCache(cache), WithStaleWhileRevalidate(0))
+
+	card, err := client.GetCard(context.Background(), "bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Name != "Stale Bolt" {
+		t.Errorf("got %q, want the revalidated cached card", card.Name)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("sent %d requests, want 1", requests.Load())
+	}
+	entry, _ := cache.Get(server.URL + "/cards/bolt")
+	if !time.Now().Before(entry.ExpiresAt) {
+		t.Error("304 did not extend the entry's expiry")
+	}
+}
+
+func TestCacheServesStaleWhileRevalidating(t *testing.T) {
+	server, requests := etagServer(&atomic.Int32{})
+	defer server.Close()
+	cache := seedCache(server, "/cards/bolt", CacheEntry{
+		Body:      []byte(staleCard),
+		ETag:      `"v1"`,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	client := newTestClient(t, server, WithCache(cache))
+
+	card, err := client.GetCard(context.Background(), "bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Name != "Stale Bolt" {
+		t.Errorf("got %q, want the stale card", card.Name)
+	}
+	waitFor(t, func() bool {
+		entry, _ := cache.Get(server.URL + "/cards/bolt")
+		return entry.ETag == `"v2"`
+	})
+	card, err = client.GetCard(context.Background(), "bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Name != "Fresh Bolt" || requests.Load() != 1 {
+		t.Errorf("got %q after %d requests, want the refreshed card after 1", card.Name, requests.Load())
+	}
+}
+
+func TestCacheFallsBackWhenUnavailable(t *testing.T) {
+	for _, test := range []struct {
+		status int
+		stale  bool
+	}{
+		{http.StatusServiceUnavailable, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusNotFound, false},
+	} {
+		status := &atomic.Int32{}
+		status.Store(int32(test.status))
+		server, _ := etagServer(status)
+		cache := seedCache(server, "/cards/bolt", CacheEntry{
+			Body:      []byte(staleCard),
+			ExpiresAt: time.Now().Add(-2 * time.Hour),
+		})
+		client := newTestClient(t, server, WithCache(cache))
+
+		card, err := client.GetCard(context.Background(), "bolt")
+		if test.stale && (err != nil || card.Name != "Stale Bolt") {
+			t.Errorf("%d: got %q, %v, want the stale card", test.status, card.Name, err)
+		}
+		if !test.stale && err == nil {
+			t.Errorf("%d: got %q, want an error", test.status, card.Name)
+		}
+		server.Close()
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", CacheEntry{ETag: "a"})
+	cache.Set("b", CacheEntry{ETag: "b"})
+	cache.Get("a")
+	cache.Set("c", CacheEntry{ETag: "c"})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if entry, ok := cache.Get(key); !ok || entry.ETag != key {
+			t.Errorf("%s: got %+v, %t", key, entry, ok)
+		}
+	}
+	cache.Delete("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Error("a should have been deleted")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := CacheEntry{
+		Body:         []byte(staleCard),
+		ETag:         `"v1"`,
+		LastModified: "Mon, 02 Jan 2006 15:04:05 GMT",
+		ExpiresAt:    time.Now().Add(time.Hour).Round(0),
+	}
+	cache.Set("https://api.scryfall.com/cards/bolt", entry)
+
+	reopened, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := reopened.Get("https://api.scryfall.com/cards/bolt")
+	if !ok || string(got.Body) != staleCard || got.ETag != entry.ETag || got.LastModified != entry.LastModified || !got.ExpiresAt.Equal(entry.ExpiresAt) {
+		t.Errorf("got %+v, %t, want %+v", got, ok, entry)
+	}
+	if _, ok := reopened.Get("https://api.scryfall.com/cards/other"); ok {
+		t.Error("got an entry for a key that was never set")
+	}
+	reopened.Delete("https://api.scryfall.com/cards/bolt")
+	if _, ok := cache.Get("https://api.scryfall.com/cards/bolt"); ok {
+		t.Error("entry survived Delete")
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	client, err := NewClient(WithBaseURI("https://api.scryfall.com/"), WithCacheTTL("cards/named", time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]time.Duration{
+		"cards/random":           0,
+		"cards/autocomplete?q=b": time.Hour,
+		"cards/named?exact=bolt": time.Minute,
+		"cards/m10/146":          10 * time.Minute,
+		"sets/m10":               24 * time.Hour,
+		"catalog/card-names":     24 * time.Hour,
+		"bulk-data":              time.Hour,
+		"migrations":             0,
+	} {
+		uri, err := url.Parse("https://api.scryfall.com/" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := client.cacheTTL(uri); got != want {
+			t.Errorf("%s: got %v, want %v", path, got, want)
+		}
+	}
+}
@@ -2,9 +2,13 @@ package scryfall
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
 	"net/url"
+	"slices"
 	"strings"
+	"sync"
 
 	qs "github.com/google/go-querystring/query"
 )
@@ -69,6 +73,66 @@ const (
 	LegalityRestricted Legality = "restricted"
 )
 
+type Format string
+
+const (
+	FormatStandard        Format = "standard"
+	FormatFuture          Format = "future"
+	FormatHistoric        Format = "historic"
+	FormatTimeless        Format = "timeless"
+	FormatGladiator       Format = "gladiator"
+	FormatPioneer         Format = "pioneer"
+	FormatExplorer        Format = "explorer"
+	FormatModern          Format = "modern"
+	FormatLegacy          Format = "legacy"
+	FormatPauper          Format = "pauper"
+	FormatVintage         Format = "vintage"
+	FormatPenny           Format = "penny"
+	FormatCommander       Format = "commander"
+	FormatOathbreaker     Format = "oathbreaker"
+	FormatStandardBrawl   Format = "standardbrawl"
+	FormatBrawl           Format = "brawl"
+	FormatAlchemy         Format = "alchemy"
+	FormatPauperCommander Format = "paupercommander"
+	FormatDuel            Format = "duel"
+	FormatOldSchool       Format = "oldschool"
+	FormatPremodern       Format = "premodern"
+	FormatPreDH           Format = "predh"
+)
+
+var knownFormats = []Format{
+	FormatStandard,
+	FormatFuture,
+	FormatHistoric,
+	FormatTimeless,
+	FormatGladiator,
+	FormatPioneer,
+	FormatExplorer,
+	FormatModern,
+	FormatLegacy,
+	FormatPauper,
+	FormatVintage,
+	FormatPenny,
+	FormatCommander,
+	FormatOathbreaker,
+	FormatStandardBrawl,
+	FormatBrawl,
+	FormatAlchemy,
+	FormatPauperCommander,
+	FormatDuel,
+	FormatOldSchool,
+	FormatPremodern,
+	FormatPreDH,
+}
+
+func Formats() []Format {
+	return slices.Clone(knownFormats)
+}
+
+func (f Format) Known() bool {
+	return slices.Contains(knownFormats, f)
+}
+
 type Frame string
 
 const (
@@ -282,17 +346,29 @@ type Prices struct {
 	Tix       string `json:"tix"`
 }
 
-type Legalities struct {
-	Standard  Legality `json:"standard"`
-	Modern    Legality `json:"modern"`
-	Pauper    Legality `json:"pauper"`
-	Pioneer   Legality `json:"pioneer"`
-	Legacy    Legality `json:"legacy"`
-	Penny     Legality `json:"penny"`
-	Vintage   Legality `json:"vintage"`
-	Duel      Legality `json:"duel"`
-	Commander Legality `json:"commander"`
-	Future    Legality `json:"future"`
+// Legalities maps each format to a card's status in it. It is a map rather
+// than a struct so formats Scryfall adds later survive decoding.
+type Legalities map[Format]Legality
+
+func (l Legalities) Get(format Format) (Legality, bool) {
+	legality, ok := l[format]
+	return legality, ok
+}
+
+// IsLegal reports whether the card may be played in format. Restricted cards
+// are legal, limited to a single copy.
+func (l Legalities) IsLegal(format Format) bool {
+	legality := l[format]
+	return legality == LegalityLegal || legality == LegalityRestricted
+}
+
+func (l Legalities) Formats() []Format {
+	formats := make([]Format, 0, len(l))
+	for format := range l {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+	return formats
 }
 
 type RelatedURIs struct {
@@ -373,6 +449,28 @@ func (c *Client) SearchCards(ctx context.Context, query string, opts SearchCards
 	return result, nil
 }
 
+// CardSearch walks every page of a search. TotalCards and Warnings reflect
+// the pages read so far by All.
+type CardSearch interface {
+	All(ctx context.Context) iter.Seq2[Card, error]
+	TotalCards() int
+	Warnings() []string
+}
+
+var _ CardSearch = (*ListIterator[Card])(nil)
+
+// SearchCardsAll sends nothing until All is ranged over, so it takes no
+// context of its own.
+func (c *Client) SearchCardsAll(query string, opts SearchCardsOptions) (CardSearch, error) {
+	values, err := qs.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+	values.Set("q", query)
+	searchURI := fmt.Sprintf("cards/search?%s", values.Encode())
+	return newListIterator[Card](c, searchURI), nil
+}
+
 func (c *Client) getCard(ctx context.Context, url string) (Card, error) {
 	card := Card{}
 	err := c.get(ctx, url, &card)
@@ -416,33 +514,191 @@ func (c *Client) GetRandomCard(ctx context.Context) (Card, error) {
 	return c.getCard(ctx, "cards/random")
 }
 
+const maxCollectionIdentifiers = 75
+
+// ErrCollectionMismatch is returned when a collection response cannot be
+// mapped back onto the identifiers that were sent.
+var ErrCollectionMismatch = errors.New("collection response does not match request")
+
+var ErrInvalidCardIdentifier = errors.New("invalid card identifier")
+
 type CardIdentifier struct {
 	ID              string `json:"id,omitempty"`
 	MTGOID          int    `json:"mtgo_id,omitempty"`
 	MultiverseID    int    `json:"multiverse_id,omitempty"`
+	OracleID        string `json:"oracle_id,omitempty"`
+	IllustrationID  string `json:"illustration_id,omitempty"`
 	Name            string `json:"name,omitempty"`
 	Set             string `json:"set,omitempty"`
 	CollectorNumber string `json:"collector_number,omitempty"`
 }
 
+func NewCardIdentifierByID(id string) CardIdentifier {
+	return CardIdentifier{ID: id}
+}
+
+func NewCardIdentifierByMTGOID(mtgoID int) CardIdentifier {
+	return CardIdentifier{MTGOID: mtgoID}
+}
+
+func NewCardIdentifierByMultiverseID(multiverseID int) CardIdentifier {
+	return CardIdentifier{MultiverseID: multiverseID}
+}
+
+func NewCardIdentifierByOracleID(oracleID string) CardIdentifier {
+	return CardIdentifier{OracleID: oracleID}
+}
+
+func NewCardIdentifierByIllustrationID(illustrationID string) CardIdentifier {
+	return CardIdentifier{IllustrationID: illustrationID}
+}
+
+func NewCardIdentifierByName(name string) CardIdentifier {
+	return CardIdentifier{Name: name}
+}
+
+func NewCardIdentifierByNameAndSet(name string, set string) CardIdentifier {
+	return CardIdentifier{Name: name, Set: set}
+}
+
+func NewCardIdentifierBySetAndCollectorNumber(set string, collectorNumber string) CardIdentifier {
+	return CardIdentifier{Set: set, CollectorNumber: collectorNumber}
+}
+
+func (i CardIdentifier) Validate() error {
+	var fields []string
+	if len(i.ID) != 0 {
+		fields = append(fields, "id")
+	}
+	if i.MTGOID != 0 {
+		fields = append(fields, "mtgo_id")
+	}
+	if i.MultiverseID != 0 {
+		fields = append(fields, "multiverse_id")
+	}
+	if len(i.OracleID) != 0 {
+		fields = append(fields, "oracle_id")
+	}
+	if len(i.IllustrationID) != 0 {
+		fields = append(fields, "illustration_id")
+	}
+	if len(i.Name) != 0 {
+		fields = append(fields, "name")
+	}
+	if len(i.CollectorNumber) != 0 {
+		fields = append(fields, "collector_number")
+	}
+	if len(i.Set) != 0 {
+		fields = append(fields, "set")
+	}
+	switch strings.Join(fields, "+") {
+	case "id", "mtgo_id", "multiverse_id", "oracle_id", "illustration_id", "name", "name+set", "collector_number+set":
+		return nil
+	case "":
+		return fmt.Errorf("%w: no fields set", ErrInvalidCardIdentifier)
+	}
+	return fmt.Errorf("%w: unsupported combination %s", ErrInvalidCardIdentifier, strings.Join(fields, "+"))
+}
+
 type GetCardsByIdentifiersRequest struct {
 	Identifiers []CardIdentifier `json:"identifiers"`
 }
 
 type GetCardsByIdentifiersResponse struct {
-	NotFound []CardIdentifier `json:"not_found"`
-	Data     []Card           `json:"data"`
+	NotFound        []CardIdentifier `json:"not_found"`
+	Data            []Card           `json:"data"`
+	NotFoundIndices []int            `json:"-"`
+	DataIndices     []int            `json:"-"`
 }
 
 func (c *Client) GetCardsByIdentifiers(ctx context.Context, identifiers []CardIdentifier) (GetCardsByIdentifiersResponse, error) {
+	for i, identifier := range identifiers {
+		err := identifier.Validate()
+		if err != nil {
+			return GetCardsByIdentifiersResponse{}, fmt.Errorf("identifier %d: %w", i, err)
+		}
+	}
+	batches := (len(identifiers) + maxCollectionIdentifiers - 1) / maxCollectionIdentifiers
+	responses := make([]GetCardsByIdentifiersResponse, batches)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sem := make(chan struct{}, max(c.collectionConcurrency, 1))
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for i := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		start := i * maxCollectionIdentifiers
+		end := min(start+maxCollectionIdentifiers, len(identifiers))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			response, err := c.getCardsBatch(ctx, identifiers[start:end], start)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			responses[i] = response
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return GetCardsByIdentifiersResponse{}, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return GetCardsByIdentifiersResponse{}, err
+	}
+	merged := GetCardsByIdentifiersResponse{
+		NotFound:        []CardIdentifier{},
+		Data:            []Card{},
+		NotFoundIndices: []int{},
+		DataIndices:     []int{},
+	}
+	for _, response := range responses {
+		merged.NotFound = append(merged.NotFound, response.NotFound...)
+		merged.Data = append(merged.Data, response.Data...)
+		merged.NotFoundIndices = append(merged.NotFoundIndices, response.NotFoundIndices...)
+		merged.DataIndices = append(merged.DataIndices, response.DataIndices...)
+	}
+	return merged, nil
+}
+
+func (c *Client) getCardsBatch(ctx context.Context, identifiers []CardIdentifier, offset int) (GetCardsByIdentifiersResponse, error) {
 	getCardsByIdentifiersRequest := GetCardsByIdentifiersRequest{
 		Identifiers: identifiers,
 	}
 	getCardsByIdentifiersResponse := GetCardsByIdentifiersResponse{}
-	err := c.post(ctx, "cards/collection", &getCardsByIdentifiersRequest, &getCardsByIdentifiersResponse)
+	err := c.post(ctx, collectionURI, &getCardsByIdentifiersRequest, &getCardsByIdentifiersResponse)
 	if err != nil {
 		return GetCardsByIdentifiersResponse{}, err
 	}
+	// The API answers in request order, echoing misses in not_found and
+	// omitting them from data, so walking both lists recovers input indices.
+	notFound, data := 0, 0
+	for i, identifier := range identifiers {
+		if notFound < len(getCardsByIdentifiersResponse.NotFound) && getCardsByIdentifiersResponse.NotFound[notFound] == identifier {
+			getCardsByIdentifiersResponse.NotFoundIndices = append(getCardsByIdentifiersResponse.NotFoundIndices, offset+i)
+			notFound++
+		} else if data < len(getCardsByIdentifiersResponse.Data) {
+			getCardsByIdentifiersResponse.DataIndices = append(getCardsByIdentifiersResponse.DataIndices, offset+i)
+			data++
+		}
+	}
+	if len(getCardsByIdentifiersResponse.DataIndices) != len(getCardsByIdentifiersResponse.Data) ||
+		len(getCardsByIdentifiersResponse.NotFoundIndices) != len(getCardsByIdentifiersResponse.NotFound) {
+		return GetCardsByIdentifiersResponse{}, fmt.Errorf("%w: %d identifiers, %d cards, %d not found",
+			ErrCollectionMismatch, len(identifiers), len(getCardsByIdentifiersResponse.Data), len(getCardsByIdentifiersResponse.NotFound))
+	}
 	return getCardsByIdentifiersResponse, nil
 }
 
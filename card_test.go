@@ -0,0 +1,164 @@
+package scryfall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/ratelimit"
+)
+
+// collectionServer answers /cards/collection with a card named after each
+// identifier, treating names starting with "missing" as not found. echo
+// rewrites not-found identifiers before they are sent back.
+func collectionServer(t *testing.T, echo func(CardIdentifier) CardIdentifier) (*httptest.Server, *atomic.Int32) {
+	requests := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		request := GetCardsByIdentifiersRequest{}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			t.Error(err)
+		}
+		if len(request.Identifiers) > maxCollectionIdentifiers {
+			t.Errorf("batch of %d identifiers", len(request.Identifiers))
+		}
+		response := GetCardsByIdentifiersResponse{
+			NotFound: []CardIdentifier{},
+			Data:     []Card{},
+		}
+		for _, identifier := range request.Identifiers {
+			if strings.HasPrefix(identifier.Name, "missing") {
+				response.NotFound = append(response.NotFound, echo(identifier))
+				continue
+			}
+			response.Data = append(response.Data, Card{Name: identifier.Name})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	return server, requests
+}
+
+func newTestClient(t *testing.T, server *httptest.Server, options ...ClientOption) *Client {
+	options = append([]ClientOption{
+		WithBaseURI(server.URL + "/"),
+		WithHTTPClient(server.Client()),
+		WithLimiter(ratelimit.NewUnlimited()),
+	}, options...)
+	client, err := NewClient(options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestGetCardsByIdentifiersBatchIndices(t *testing.T) {
+	server, requests := collectionServer(t, func(identifier CardIdentifier) CardIdentifier { return identifier })
+	defer server.Close()
+	client := newTestClient(t, server, WithCollectionConcurrency(3))
+
+	identifiers := []CardIdentifier{}
+	for i := range 180 {
+		name := "card"
+		if i%7 == 0 {
+			name = "missing"
+		}
+		identifiers = append(identifiers, NewCardIdentifierByName(name+string(rune('a'+i%26))+strings.Repeat("x", i/26)))
+	}
+	response, err := client.GetCardsByIdentifiers(context.Background(), identifiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("sent %d requests, want 3", got)
+	}
+	if len(response.DataIndices) != len(response.Data) || len(response.NotFoundIndices) != len(response.NotFound) {
+		t.Fatalf("indices do not line up: %d/%d data, %d/%d not found",
+			len(response.DataIndices), len(response.Data), len(response.NotFoundIndices), len(response.NotFound))
+	}
+	for i, card := range response.Data {
+		if want := identifiers[response.DataIndices[i]].Name; card.Name != want {
+			t.Errorf("data %d mapped to %q, want %q", i, want, card.Name)
+		}
+	}
+	for i, identifier := range response.NotFound {
+		if identifiers[response.NotFoundIndices[i]] != identifier {
+			t.Errorf("not found %d mapped to %+v, want %+v", i, identifiers[response.NotFoundIndices[i]], identifier)
+		}
+	}
+}
+
+func TestGetCardsByIdentifiersEchoMismatch(t *testing.T) {
+	server, _ := collectionServer(t, func(identifier CardIdentifier) CardIdentifier {
+		identifier.Name = strings.ToUpper(identifier.Name)
+		return identifier
+	})
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	identifiers := []CardIdentifier{
+		NewCardIdentifierByName("bolt"),
+		NewCardIdentifierByName("missing"),
+		NewCardIdentifierByName("shock"),
+	}
+	_, err := client.GetCardsByIdentifiers(context.Background(), identifiers)
+	if !errors.Is(err, ErrCollectionMismatch) {
+		t.Fatalf("error = %v, want ErrCollectionMismatch", err)
+	}
+}
+
+func TestCardIdentifierValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier CardIdentifier
+		valid      bool
+	}{
+		{"id", NewCardIdentifierByID("56ebc372-aabd-4174-a943-c7bf59e5028d"), true},
+		{"mtgo id", NewCardIdentifierByMTGOID(33328), true},
+		{"multiverse id", NewCardIdentifierByMultiverseID(209), true},
+		{"oracle id", NewCardIdentifierByOracleID("4457ed35-7c10-48c8-9776-456485fdf070"), true},
+		{"illustration id", NewCardIdentifierByIllustrationID("b9fd8d3b-ce5e-4b1b-9fa0-53f2d5c0e0a8"), true},
+		{"name", NewCardIdentifierByName("Lightning Bolt"), true},
+		{"name and set", NewCardIdentifierByNameAndSet("Lightning Bolt", "m10"), true},
+		{"set and collector number", NewCardIdentifierBySetAndCollectorNumber("m10", "146"), true},
+		{"empty", CardIdentifier{}, false},
+		{"collector number alone", CardIdentifier{CollectorNumber: "146"}, false},
+		{"set alone", CardIdentifier{Set: "m10"}, false},
+		{"id and name", CardIdentifier{ID: "56ebc372-aabd-4174-a943-c7bf59e5028d", Name: "Lightning Bolt"}, false},
+		{"oracle id and set", CardIdentifier{OracleID: "4457ed35-7c10-48c8-9776-456485fdf070", Set: "m10"}, false},
+		{"name and collector number", CardIdentifier{Name: "Lightning Bolt", CollectorNumber: "146", Set: "m10"}, false},
+	}
+	for _, test := range tests {
+		err := test.identifier.Validate()
+		if test.valid && err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if !test.valid && !errors.Is(err, ErrInvalidCardIdentifier) {
+			t.Errorf("%s: got %v, want ErrInvalidCardIdentifier", test.name, err)
+		}
+	}
+}
+
+func TestGetCardsByIdentifiersValidatesFirst(t *testing.T) {
+	server, requests := collectionServer(t, func(identifier CardIdentifier) CardIdentifier { return identifier })
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	identifiers := make([]CardIdentifier, 0, 2*maxCollectionIdentifiers)
+	for range 2 * maxCollectionIdentifiers {
+		identifiers = append(identifiers, NewCardIdentifierByName("Lightning Bolt"))
+	}
+	identifiers[len(identifiers)-1] = CardIdentifier{CollectorNumber: "146"}
+	_, err := client.GetCardsByIdentifiers(context.Background(), identifiers)
+	if !errors.Is(err, ErrInvalidCardIdentifier) {
+		t.Errorf("got %v, want ErrInvalidCardIdentifier", err)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("sent %d requests, want none", got)
+	}
+}
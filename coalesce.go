@@ -0,0 +1,84 @@
+package scryfall
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// uncoalescedPaths return a different result on every request, so callers
+// racing on them must not share a response.
+var uncoalescedPaths = []string{"cards/random"}
+
+type inflightCall struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	resp    *response
+	err     error
+}
+
+type inflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+// doShared runs identical concurrent GET requests once. The shared request
+// runs detached from any single caller and is only cancelled once every
+// caller waiting on it has given up.
+func (c *Client) doShared(ctx context.Context, req *http.Request) (*response, error) {
+	if c.inflight == nil || !c.coalescable(req.URL) {
+		return c.do(ctx, req, true)
+	}
+	key := req.Method + " " + req.URL.String() + " " + req.Header.Get("If-None-Match") + " " + req.Header.Get("If-Modified-Since")
+	g := c.inflight
+	g.mu.Lock()
+	call, ok := g.calls[key]
+	if !ok {
+		sharedCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &inflightCall{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		g.calls[key] = call
+		go func() {
+			call.resp, call.err = c.do(sharedCtx, req, true)
+			g.mu.Lock()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+	select {
+	case <-call.done:
+		return call.resp, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Client) coalescable(uri *url.URL) bool {
+	path := c.relativePath(uri)
+	for _, prefix := range uncoalescedPaths {
+		if strings.HasPrefix(path, prefix) {
+			return false
+		}
+	}
+	return true
+}
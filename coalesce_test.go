@@ -0,0 +1,142 @@
+package scryfall
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingServer holds every request until release is closed, so that
+// concurrent callers are guaranteed to overlap.
+func blockingServer() (*httptest.Server, *atomic.Int32, chan struct{}) {
+	requests := &atomic.Int32{}
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{"object":"card","name":"Lightning Bolt"}`))
+	}))
+	return server, requests, release
+}
+
+func TestCoalescedRequests(t *testing.T) {
+	server, requests, release := blockingServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	const callers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			card, err := client.GetCard(context.Background(), "bolt")
+			if err == nil && card.Name != "Lightning Bolt" {
+				err = errors.New("wrong card " + card.Name)
+			}
+			errs <- err
+		}()
+	}
+	waitFor(t, func() bool { return requests.Load() == 1 })
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+}
+
+func TestCoalescedRequestSurvivesOneCancellation(t *testing.T) {
+	server, requests, release := blockingServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := client.GetCard(ctx, "bolt")
+		cancelled <- err
+	}()
+	waitFor(t, func() bool { return requests.Load() == 1 })
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetCard(context.Background(), "bolt")
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller got %v, want context.Canceled", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("remaining caller got %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+}
+
+func TestCoalescingDisabled(t *testing.T) {
+	server, requests, release := blockingServer()
+	defer server.Close()
+	client := newTestClient(t, server, WithRequestCoalescing(false))
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.GetCard(context.Background(), "bolt")
+		}()
+	}
+	waitFor(t, func() bool { return requests.Load() == 3 })
+	close(release)
+	wg.Wait()
+}
+
+func TestRandomCardsAreNotCoalesced(t *testing.T) {
+	server, requests, release := blockingServer()
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.GetRandomCard(context.Background())
+		}()
+	}
+	waitFor(t, func() bool { return requests.Load() == callers })
+	close(release)
+	wg.Wait()
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
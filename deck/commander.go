@@ -0,0 +1,394 @@
+package deck
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/tencorvids/scryfall"
+)
+
+const (
+	ViolationCommander     ViolationKind = "commander"
+	ViolationPairing       ViolationKind = "pairing"
+	ViolationColorIdentity ViolationKind = "color_identity"
+	ViolationCompanion     ViolationKind = "companion"
+)
+
+var (
+	partnerWithPattern  = regexp.MustCompile(`(?m)^Partner with ([^(\n]+?)\s*(?:\(|$)`)
+	partnerGroupPattern = regexp.MustCompile(`(?m)^Partner—([^(\n]+?)\s*(?:\(|$)`)
+	companionPattern    = regexp.MustCompile(`(?m)^Companion — (.+)$`)
+)
+
+// ValidateCommander checks a resolved deck against the Commander rules on
+// top of the format checks done by Validate: commander eligibility, partner
+// style pairings, color identity and companion deckbuilding conditions.
+func ValidateCommander(report *Report) ([]Violation, error) {
+	violations, err := Validate(report, scryfall.FormatCommander)
+	if err != nil {
+		return nil, err
+	}
+
+	commanders := []ResolvedEntry{}
+	deck := []ResolvedEntry{}
+	companions := []ResolvedEntry{}
+	for _, resolved := range report.Entries {
+		if resolved.Card == nil {
+			continue
+		}
+		switch resolved.Entry.Section {
+		case SectionCommander:
+			commanders = append(commanders, resolved)
+		case SectionCompanion:
+			companions = append(companions, resolved)
+		case SectionMain:
+			deck = append(deck, resolved)
+		}
+	}
+
+	violations = append(violations, checkCommanders(commanders)...)
+
+	identity := map[scryfall.Color]bool{}
+	for _, commander := range commanders {
+		for _, color := range commander.Card.ColorIdentity {
+			identity[color] = true
+		}
+	}
+	if len(commanders) != 0 {
+		for _, resolved := range slices.Concat(deck, companions) {
+			outside := []string{}
+			for _, color := range resolved.Card.ColorIdentity {
+				if !identity[color] {
+					outside = append(outside, string(color))
+				}
+			}
+			if len(outside) != 0 {
+				violations = append(violations, entryViolation(ViolationColorIdentity, resolved, "%s has %s outside the commander's color identity", resolved.Card.Name, strings.Join(outside, "")))
+			}
+		}
+	}
+
+	starting := slices.Concat(commanders, deck)
+	for _, companion := range companions {
+		violations = append(violations, checkCompanion(companion, starting)...)
+	}
+	return violations, nil
+}
+
+func checkCommanders(commanders []ResolvedEntry) []Violation {
+	violations := []Violation{}
+	count := 0
+	for _, commander := range commanders {
+		count += commander.Entry.Quantity
+	}
+	switch {
+	case count == 0:
+		return []Violation{{Kind: ViolationCommander, Message: "deck has no commander"}}
+	case count > 2:
+		violation := Violation{
+			Kind:    ViolationCommander,
+			Message: fmt.Sprintf("deck has %d commanders, at most 2 allowed", count),
+		}
+		for _, commander := range commanders {
+			violation.Cards = append(violation.Cards, commander.Card.Name)
+			violation.Lines = append(violation.Lines, commander.Entry.Line)
+		}
+		return []Violation{violation}
+	case count == 2 && len(commanders) == 1:
+		return []Violation{entryViolation(ViolationCommander, commanders[0], "%s cannot be both commanders", commanders[0].Card.Name)}
+	}
+
+	for _, commander := range commanders {
+		if isBackground(commander.Card) && len(commanders) == 2 {
+			continue
+		}
+		if !canBeCommander(commander.Card) {
+			violations = append(violations, entryViolation(ViolationCommander, commander, "%s cannot be your commander", commander.Card.Name))
+		}
+	}
+	if len(commanders) == 2 && !canPair(commanders[0].Card, commanders[1].Card) {
+		violations = append(violations, Violation{
+			Kind:    ViolationPairing,
+			Message: fmt.Sprintf("%s and %s cannot be paired as commanders", commanders[0].Card.Name, commanders[1].Card.Name),
+			Cards:   []string{commanders[0].Card.Name, commanders[1].Card.Name},
+			Lines:   []int{commanders[0].Entry.Line, commanders[1].Entry.Line},
+		})
+	}
+	return violations
+}
+
+func canBeCommander(card *scryfall.Card) bool {
+	typeLine := frontTypeLine(card)
+	if strings.Contains(typeLine, "Legendary") && strings.Contains(typeLine, "Creature") {
+		return true
+	}
+	return strings.Contains(oracleText(card), "can be your commander")
+}
+
+func canPair(a *scryfall.Card, b *scryfall.Card) bool {
+	return pairsWith(a, b) || pairsWith(b, a)
+}
+
+// pairsWith reports whether a's pairing ability allows b as the other
+// commander. Symmetric abilities are checked on both cards by canPair.
+// Abilities come from the card's keywords; oracle text only supplies the
+// partner's name or group.
+func pairsWith(a *scryfall.Card, b *scryfall.Card) bool {
+	if plainPartner(a) && plainPartner(b) {
+		return true
+	}
+	if hasKeyword(a, "Partner with") {
+		m := partnerWithPattern.FindStringSubmatch(oracleText(a))
+		if m != nil && strings.EqualFold(m[1], b.Name) {
+			return true
+		}
+	}
+	if groupA, ok := partnerGroup(a); ok {
+		groupB, ok := partnerGroup(b)
+		if ok && strings.EqualFold(groupA, groupB) {
+			return true
+		}
+	}
+	if hasKeyword(a, "Friends forever") && hasKeyword(b, "Friends forever") {
+		return true
+	}
+	if hasKeyword(a, "Choose a Background") && isBackground(b) {
+		return true
+	}
+	if hasKeyword(a, "Doctor's companion") && isDoctor(b) {
+		return true
+	}
+	return false
+}
+
+func hasKeyword(card *scryfall.Card, keyword string) bool {
+	return slices.ContainsFunc(card.Keywords, func(k string) bool {
+		return strings.EqualFold(k, keyword)
+	})
+}
+
+// plainPartner reports whether card has partner without a named partner or
+// a partner group, either of which restricts what it can pair with.
+func plainPartner(card *scryfall.Card) bool {
+	if !hasKeyword(card, "Partner") || hasKeyword(card, "Partner with") {
+		return false
+	}
+	_, ok := partnerGroup(card)
+	return !ok
+}
+
+func partnerGroup(card *scryfall.Card) (string, bool) {
+	if !slices.ContainsFunc(card.Keywords, func(k string) bool {
+		return strings.HasPrefix(strings.ToLower(k), "partner")
+	}) {
+		return "", false
+	}
+	m := partnerGroupPattern.FindStringSubmatch(oracleText(card))
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+func isBackground(card *scryfall.Card) bool {
+	return slices.Contains(subtypes(card), "Background") && strings.Contains(frontTypeLine(card), "Legendary")
+}
+
+// isDoctor reports whether card is a legendary Time Lord Doctor creature
+// with no other creature types, as doctor's companion requires.
+func isDoctor(card *scryfall.Card) bool {
+	return canBeCommander(card) && slices.Equal(subtypes(card), []string{"Time", "Lord", "Doctor"})
+}
+
+func frontTypeLine(card *scryfall.Card) string {
+	typeLine, _, _ := strings.Cut(card.TypeLine, " // ")
+	return typeLine
+}
+
+func subtypes(card *scryfall.Card) []string {
+	_, subtypes, _ := strings.Cut(frontTypeLine(card), "—")
+	return strings.Fields(subtypes)
+}
+
+var cardTypes = []string{"Artifact", "Battle", "Creature", "Enchantment", "Instant", "Kindred", "Land", "Planeswalker", "Sorcery", "Tribal"}
+
+var permanentTypes = []string{"Artifact", "Battle", "Creature", "Enchantment", "Land", "Planeswalker"}
+
+func types(card *scryfall.Card) []string {
+	supertypes, _, _ := strings.Cut(frontTypeLine(card), "—")
+	found := []string{}
+	for _, word := range strings.Fields(supertypes) {
+		if slices.Contains(cardTypes, word) {
+			found = append(found, word)
+		}
+	}
+	return found
+}
+
+func hasType(card *scryfall.Card, typ ...string) bool {
+	for _, t := range types(card) {
+		if slices.Contains(typ, t) {
+			return true
+		}
+	}
+	return false
+}
+
+type companionCheck struct {
+	condition *regexp.Regexp
+	allows    func(card *scryfall.Card, starting []ResolvedEntry) bool
+}
+
+var companionChecks = []companionCheck{
+	{
+		// Gyruda, Doom of Depths.
+		condition: regexp.MustCompile(`only cards with even mana values`),
+		allows: func(card *scryfall.Card, _ []ResolvedEntry) bool {
+			return int(card.CMC)%2 == 0
+		},
+	},
+	{
+		// Obosh, the Preypiercer.
+		condition: regexp.MustCompile(`only cards with odd mana values and land cards`),
+		allows: func(card *scryfall.Card, _ []ResolvedEntry) bool {
+			return int(card.CMC)%2 == 1 || hasType(card, "Land")
+		},
+	},
+	{
+		// Jegantha, the Wellspring.
+		condition: regexp.MustCompile(`more than one of the same mana symbol in its mana cost`),
+		allows: func(card *scryfall.Card, _ []ResolvedEntry) bool {
+			costs := []string{card.ManaCost}
+			for _, face := range card.CardFaces {
+				costs = append(costs, face.ManaCost)
+			}
+			for _, cost := range costs {
+				symbols, err := scryfall.ParseManaSymbols(cost)
+				if err != nil {
+					continue
+				}
+				seen := map[string]bool{}
+				for _, symbol := range symbols {
+					if seen[symbol.Symbol] {
+						return false
+					}
+					seen[symbol.Symbol] = true
+				}
+			}
+			return true
+		},
+	},
+	{
+		// Kaheera, the Orphanguard.
+		condition: regexp.MustCompile(`Each creature card in your starting deck is a Cat, Elemental, Nightmare, Dinosaur, or Beast card`),
+		allows: func(card *scryfall.Card, _ []ResolvedEntry) bool {
+			if !hasType(card, "Creature") {
+				return true
+			}
+			for _, subtype := range subtypes(card) {
+				if slices.Contains([]string{"Cat", "Elemental", "Nightmare", "Dinosaur", "Beast"}, subtype) {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		// Keruga, the Macrosage.
+		condition: regexp.MustCompile(`Each nonland card in your starting deck has mana value 3 or greater`),
+		allows: func(card *scryfall.Card, _ []ResolvedEntry) bool {
+			return hasType(card, "Land") || card.CMC >= 3
+		},
+	},
+	{
+		// Lurrus of the Dream-Den.
+		condition: regexp.MustCompile(`Each permanent card in your starting deck has mana value 2 or less`),
+		allows: func(card *scryfall.Card, _ []ResolvedEntry) bool {
+			return !hasType(card, permanentTypes...) || card.CMC <= 2
+		},
+	},
+	{
+		// Umori, the Collector.
+		condition: regexp.MustCompile(`Each nonland card in your starting deck shares a card type`),
+		allows: func(card *scryfall.Card, starting []ResolvedEntry) bool {
+			if hasType(card, "Land") {
+				return true
+			}
+			shared := slices.Clone(types(card))
+			for _, resolved := range starting {
+				if hasType(resolved.Card, "Land") {
+					continue
+				}
+				shared = slices.DeleteFunc(shared, func(t string) bool {
+					return !hasType(resolved.Card, t)
+				})
+			}
+			return len(shared) != 0
+		},
+	},
+	{
+		// Zirda, the Dawnwaker. Besides explicit costs, only the common
+		// activated keyword abilities such as equip and crew are recognized.
+		condition: regexp.MustCompile(`Each permanent card in your starting deck has an activated ability`),
+		allows: func(card *scryfall.Card, _ []ResolvedEntry) bool {
+			if !hasType(card, permanentTypes...) {
+				return true
+			}
+			return activatedAbilityPattern.MatchString(oracleText(card))
+		},
+	},
+	{
+		// Yorion, Sky Nomad. A Commander deck is always exactly 100 cards.
+		condition: regexp.MustCompile(`at least twenty cards more than the minimum deck size`),
+		allows: func(*scryfall.Card, []ResolvedEntry) bool {
+			return false
+		},
+	},
+}
+
+var activatedAbilityPattern = regexp.MustCompile(`(?m)^(?:[^"(\n]*?[^"(\n:]):|^(?:Equip|Crew|Fortify|Reconfigure|Outlast|Level up)\b`)
+
+// checkCompanion evaluates the companion's condition from its oracle text.
+// Conditions that are always met in Commander, such as Lutri's, and ones
+// this package does not recognize produce no violations.
+func checkCompanion(companion ResolvedEntry, starting []ResolvedEntry) []Violation {
+	m := companionPattern.FindStringSubmatch(oracleText(companion.Card))
+	if m == nil {
+		return []Violation{entryViolation(ViolationCompanion, companion, "%s does not have companion", companion.Card.Name)}
+	}
+	for _, check := range companionChecks {
+		if !check.condition.MatchString(m[1]) {
+			continue
+		}
+		violation := Violation{
+			Kind:    ViolationCompanion,
+			Message: fmt.Sprintf("%s's companion condition is not met", companion.Card.Name),
+			Cards:   []string{companion.Card.Name},
+			Lines:   []int{companion.Entry.Line},
+		}
+		failed := false
+		for _, resolved := range starting {
+			if !check.allows(resolved.Card, starting) {
+				failed = true
+				violation.Cards = append(violation.Cards, resolved.Card.Name)
+				violation.Lines = append(violation.Lines, resolved.Entry.Line)
+			}
+		}
+		if failed || len(starting) == 0 {
+			return []Violation{violation}
+		}
+		return nil
+	}
+	return nil
+}
+
+func entryViolation(kind ViolationKind, resolved ResolvedEntry, format string, args ...any) Violation {
+	return Violation{
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+		Cards:   []string{resolved.Card.Name},
+		Lines:   []int{resolved.Entry.Line},
+	}
+}
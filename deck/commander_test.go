@@ -0,0 +1,125 @@
+package deck
+
+import (
+	"testing"
+
+	"github.com/tencorvids/scryfall"
+)
+
+func commanderCard(name string, typeLine string, oracleText string, keywords []string, identity ...scryfall.Color) *scryfall.Card {
+	return &scryfall.Card{
+		OracleID:      name,
+		Name:          name,
+		TypeLine:      typeLine,
+		OracleText:    oracleText,
+		Keywords:      keywords,
+		ColorIdentity: identity,
+		Legalities:    scryfall.Legalities{scryfall.FormatCommander: scryfall.LegalityLegal},
+	}
+}
+
+func commanderReport(commanders []*scryfall.Card, companion *scryfall.Card, main ...*scryfall.Card) *Report {
+	report := &Report{}
+	line := 1
+	add := func(section Section, quantity int, card *scryfall.Card) {
+		report.Entries = append(report.Entries, ResolvedEntry{
+			Entry:      Entry{Line: line, Section: section, Quantity: quantity, Name: card.Name},
+			Card:       card,
+			Resolution: ResolutionExact,
+		})
+		line++
+	}
+	for _, card := range commanders {
+		add(SectionCommander, 1, card)
+	}
+	if companion != nil {
+		add(SectionCompanion, 1, companion)
+	}
+	for _, card := range main {
+		add(SectionMain, 1, card)
+	}
+	mountain := commanderCard("Mountain", "Basic Land — Mountain", "", nil)
+	add(SectionMain, 100-len(commanders)-len(main), mountain)
+	return report
+}
+
+func kinds(violations []Violation) []ViolationKind {
+	kinds := []ViolationKind{}
+	for _, violation := range violations {
+		kinds = append(kinds, violation.Kind)
+	}
+	return kinds
+}
+
+func TestValidateCommanderPairing(t *testing.T) {
+	const creature = "Legendary Creature — Human"
+	thrasios := commanderCard("Thrasios", creature, "Partner (You can have two commanders if both have partner.)", []string{"Partner"})
+	tymna := commanderCard("Tymna", creature, "Partner (You can have two commanders if both have partner.)", []string{"Partner"})
+	pir := commanderCard("Pir, Imaginative Rascal", creature, "Partner with Toothy, Imaginary Friend (When this creature enters, target player may put Toothy into their hand from their library, then shuffle.)", []string{"Partner with"})
+	toothy := commanderCard("Toothy, Imaginary Friend", "Legendary Creature — Illusion", "Partner with Pir, Imaginative Rascal (When this creature enters, target player may put Pir into their hand from their library, then shuffle.)", []string{"Partner with"})
+	wilson := commanderCard("Wilson, Refined Grizzly", "Legendary Creature — Bear Warrior", "Choose a Background (You can have a Background as a second commander.)", []string{"Choose a Background"})
+	background := commanderCard("Agent of the Shadow Thieves", "Legendary Enchantment — Background", "Commander creatures you own have deathtouch.", nil)
+	doctor := commanderCard("The Tenth Doctor", "Legendary Creature — Time Lord Doctor", "Allons-y!", nil)
+	rose := commanderCard("Rose Tyler", creature, "Doctor's companion (You can have two commanders if the other is the Doctor.)", []string{"Doctor's companion"})
+	friend := commanderCard("Will the Wise", creature, "Friends forever (You can have two commanders if both have friends forever.)", []string{"Friends forever"})
+	otherFriend := commanderCard("Mike, the Dungeon Master", creature, "Friends forever (You can have two commanders if both have friends forever.)", []string{"Friends forever"})
+	survivor := commanderCard("Tyvar", creature, "Partner—Survivors (You can have two commanders if both have this ability.)", []string{"Partner—Survivors"})
+	otherSurvivor := commanderCard("Wick", creature, "Partner—Survivors (You can have two commanders if both have this ability.)", []string{"Partner—Survivors"})
+	// Oracle text that merely mentions partner, without the keyword.
+	impostor := commanderCard("Impostor", creature, "Partner (You can have two commanders if both have partner.)", nil)
+
+	tests := []struct {
+		name  string
+		a, b  *scryfall.Card
+		valid bool
+	}{
+		{"partner", thrasios, tymna, true},
+		{"partner with", pir, toothy, true},
+		{"partner with a stranger", pir, thrasios, false},
+		{"choose a background", wilson, background, true},
+		{"background alone", thrasios, background, false},
+		{"doctor's companion", rose, doctor, true},
+		{"friends forever", friend, otherFriend, true},
+		{"friends forever with partner", friend, thrasios, false},
+		{"partner group", survivor, otherSurvivor, true},
+		{"partner group with partner", survivor, thrasios, false},
+		{"partner text without keyword", impostor, thrasios, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			violations, err := ValidateCommander(commanderReport([]*scryfall.Card{test.a, test.b}, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if valid := len(violations) == 0; valid != test.valid {
+				t.Errorf("violations %v, want valid = %v", kinds(violations), test.valid)
+			}
+		})
+	}
+}
+
+func TestValidateCommanderColorIdentityAndCompanion(t *testing.T) {
+	krenko := commanderCard("Krenko, Mob Boss", "Legendary Creature — Goblin Warrior", "{T}: Create X 1/1 red Goblin creature tokens.", nil, "R")
+	counterspell := commanderCard("Counterspell", "Instant", "Counter target spell.", nil, "U")
+	lurrus := commanderCard("Lurrus of the Dream-Den", "Legendary Creature — Cat Nightmare", "Companion — Each permanent card in your starting deck has mana value 2 or less.\nLifelink", []string{"Companion", "Lifelink"}, "W", "B")
+	lurrus.CMC = 3
+	krenko.CMC = 4
+
+	violations, err := ValidateCommander(commanderReport([]*scryfall.Card{krenko}, lurrus, counterspell))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := kinds(violations)
+	want := []ViolationKind{ViolationColorIdentity, ViolationColorIdentity, ViolationCompanion}
+	if len(got) != len(want) {
+		t.Fatalf("violations %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("violations %v, want %v", got, want)
+		}
+	}
+	if cards := violations[2].Cards; len(cards) != 2 || cards[1] != krenko.Name {
+		t.Errorf("companion violation names %v, want Lurrus and Krenko", cards)
+	}
+}
@@ -0,0 +1,145 @@
+package deck
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var csvColumns = map[string]string{
+	"count":            "quantity",
+	"quantity":         "quantity",
+	"qty":              "quantity",
+	"name":             "name",
+	"card name":        "name",
+	"edition":          "set",
+	"edition code":     "set",
+	"set":              "set",
+	"set code":         "set",
+	"collector number": "collector_number",
+	"collector_number": "collector_number",
+	"scryfall id":      "scryfall_id",
+	"scryfall_id":      "scryfall_id",
+	"mtgo id":          "mtgo_id",
+	"board":            "section",
+	"section":          "section",
+	"category":         "section",
+	"categories":       "section",
+}
+
+func isCSVHeader(line string) bool {
+	fields, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return false
+	}
+	columns := csvHeader(fields)
+	_, hasName := columns["name"]
+	_, hasQuantity := columns["quantity"]
+	return hasName && hasQuantity
+}
+
+func csvHeader(fields []string) map[string]int {
+	columns := map[string]int{}
+	for i, field := range fields {
+		column, ok := csvColumns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(field, bom)))]
+		if !ok {
+			continue
+		}
+		if _, seen := columns[column]; !seen {
+			columns[column] = i
+		}
+	}
+	return columns
+}
+
+// ParseCSV parses Moxfield and Archidekt style CSV exports. Columns are
+// matched by header name, so extra columns and either site's ordering work.
+func ParseCSV(r io.Reader) (*Deck, error) {
+	d := &Deck{Entries: []Entry{}}
+	parseErr := &ParseError{}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := csvHeader(header)
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("deck: CSV header has no name column")
+	}
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var csvErr *csv.ParseError
+		if errors.As(err, &csvErr) {
+			parseErr.add(csvErr.StartLine, "", csvErr.Err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		entry := Entry{
+			Line:            line,
+			Quantity:        1,
+			Name:            field(record, "name"),
+			Set:             strings.ToLower(field(record, "set")),
+			CollectorNumber: field(record, "collector_number"),
+			ScryfallID:      field(record, "scryfall_id"),
+		}
+		section, ok := csvSection(field(record, "section"))
+		if !ok {
+			continue
+		}
+		entry.Section = section
+		if quantity := field(record, "quantity"); len(quantity) != 0 {
+			entry.Quantity, err = strconv.Atoi(quantity)
+			if err != nil || entry.Quantity <= 0 {
+				parseErr.add(line, strings.Join(record, ","), ErrInvalidQuantity)
+				continue
+			}
+		}
+		if mtgoID := field(record, "mtgo_id"); len(mtgoID) != 0 {
+			entry.MTGOID, _ = strconv.Atoi(mtgoID)
+		}
+		if len(entry.Name) == 0 {
+			parseErr.add(line, strings.Join(record, ","), ErrMissingName)
+			continue
+		}
+		d.Entries = append(d.Entries, entry)
+	}
+	return d, parseErr.err()
+}
+
+// csvSection maps a board or category column onto a section. Archidekt
+// stores a comma-separated list of categories, so any matching category
+// wins. Maybeboard rows are not part of the deck and are dropped.
+func csvSection(value string) (Section, bool) {
+	section := SectionMain
+	for _, category := range strings.Split(value, ",") {
+		switch strings.ToLower(strings.TrimSpace(category)) {
+		case "maybeboard", "maybe", "considering":
+			return "", false
+		case "sideboard", "side":
+			section = SectionSideboard
+		case "commander", "commanders":
+			return SectionCommander, true
+		case "companion", "companions":
+			return SectionCompanion, true
+		}
+	}
+	return section, true
+}
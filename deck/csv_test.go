@@ -0,0 +1,106 @@
+package deck
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	src := "\"Count\",\"Tradelist Count\",\"Name\",\"Edition\",\"Collector Number\"\n" +
+		"\"4\",\"0\",\"Lightning Bolt\",\"M10\",\"146\"\n" +
+		"\"1\",\"0\",\"Fire // Ice\",\"\",\"\"\n"
+	d, err := ParseCSV(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{Line: 2, Section: SectionMain, Quantity: 4, Name: "Lightning Bolt", Set: "m10", CollectorNumber: "146"},
+		{Line: 3, Section: SectionMain, Quantity: 1, Name: "Fire // Ice"},
+	}
+	if len(d.Entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(d.Entries), len(want))
+	}
+	for i := range want {
+		if d.Entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, d.Entries[i], want[i])
+		}
+	}
+}
+
+func TestParseCSVSections(t *testing.T) {
+	src := "Quantity,Name,Categories\n" +
+		"1,Krenko,\"Commander,Goblins\"\n" +
+		"1,Maybe,Maybeboard\n" +
+		"2,Pyroblast,\"Burn,Sideboard\"\n"
+	d, err := ParseCSV(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Count(SectionCommander); got != 1 {
+		t.Errorf("commander count = %d, want 1", got)
+	}
+	if got := d.Count(SectionSideboard); got != 2 {
+		t.Errorf("sideboard count = %d, want 2", got)
+	}
+	if got := len(d.Entries); got != 2 {
+		t.Errorf("got %d entries, want maybeboard dropped", got)
+	}
+}
+
+func TestParseCSVLineErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		lines []int
+		err   error
+	}{
+		{
+			name:  "bare quote in first field",
+			src:   "Count,Name\n4,Lightning Bolt\na\"b,Shock\n1,Opt\n",
+			lines: []int{3},
+			err:   csv.ErrBareQuote,
+		},
+		{
+			name:  "unterminated quote at EOF",
+			src:   "Count,Name\n4,Lightning Bolt\n\"1,Shock\n",
+			lines: []int{3},
+			err:   csv.ErrQuote,
+		},
+		{
+			name:  "invalid quantity",
+			src:   "Count,Name\nx,Shock\n4,Lightning Bolt\n",
+			lines: []int{2},
+			err:   ErrInvalidQuantity,
+		},
+		{
+			name:  "missing name",
+			src:   "Count,Name\n1,\n",
+			lines: []int{2},
+			err:   ErrMissingName,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := ParseCSV(strings.NewReader(test.src))
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("error = %v, want *ParseError", err)
+			}
+			if !errors.Is(err, test.err) {
+				t.Errorf("error = %v, want %v", err, test.err)
+			}
+			lines := []int{}
+			for _, lineErr := range parseErr.Errors {
+				lines = append(lines, lineErr.Line)
+			}
+			if len(lines) != len(test.lines) || lines[0] != test.lines[0] {
+				t.Errorf("error lines = %v, want %v", lines, test.lines)
+			}
+			if d == nil {
+				t.Fatal("deck is nil, want the rows that parsed")
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package deck
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tencorvids/scryfall"
+)
+
+// bom is stripped from the start of exports saved by Windows tools.
+const bom = "\ufeff"
+
+type Section string
+
+const (
+	SectionMain      Section = "main"
+	SectionSideboard Section = "sideboard"
+	SectionCommander Section = "commander"
+	SectionCompanion Section = "companion"
+)
+
+type Entry struct {
+	Line            int
+	Section         Section
+	Quantity        int
+	Name            string
+	Set             string
+	CollectorNumber string
+	ScryfallID      string
+	MTGOID          int
+}
+
+// Identifier returns the most specific identifier the entry carries.
+func (e Entry) Identifier() scryfall.CardIdentifier {
+	switch {
+	case len(e.ScryfallID) != 0:
+		return scryfall.NewCardIdentifierByID(e.ScryfallID)
+	case e.MTGOID != 0:
+		return scryfall.NewCardIdentifierByMTGOID(e.MTGOID)
+	case len(e.Set) != 0 && len(e.CollectorNumber) != 0:
+		return scryfall.NewCardIdentifierBySetAndCollectorNumber(e.Set, e.CollectorNumber)
+	case len(e.Set) != 0:
+		return scryfall.NewCardIdentifierByNameAndSet(e.Name, e.Set)
+	default:
+		return scryfall.NewCardIdentifierByName(e.Name)
+	}
+}
+
+type Deck struct {
+	Name    string
+	Entries []Entry
+}
+
+func (d *Deck) Section(section Section) []Entry {
+	entries := []Entry{}
+	for _, entry := range d.Entries {
+		if entry.Section == section {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func (d *Deck) Count(section Section) int {
+	count := 0
+	for _, entry := range d.Entries {
+		if entry.Section == section {
+			count += entry.Quantity
+		}
+	}
+	return count
+}
+
+// Identifiers returns one identifier per entry, in entry order, so that the
+// indices reported by GetCardsByIdentifiers map straight back onto Entries.
+func (d *Deck) Identifiers() []scryfall.CardIdentifier {
+	identifiers := make([]scryfall.CardIdentifier, 0, len(d.Entries))
+	for _, entry := range d.Entries {
+		identifiers = append(identifiers, entry.Identifier())
+	}
+	return identifiers
+}
+
+var (
+	ErrInvalidQuantity = errors.New("invalid quantity")
+	ErrMissingName     = errors.New("missing card name")
+)
+
+type LineError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("deck: line %d: %v: %q", e.Line, e.Err, e.Text)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError collects every malformed line. The deck returned alongside it
+// still holds the lines that did parse.
+type ParseError struct {
+	Errors []*LineError
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%v (and %d more errors)", e.Errors[0], len(e.Errors)-1)
+}
+
+func (e *ParseError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func (e *ParseError) add(line int, text string, err error) {
+	e.Errors = append(e.Errors, &LineError{Line: line, Text: text, Err: err})
+}
+
+func (e *ParseError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+type ListFormat int
+
+const (
+	ListFormatText ListFormat = iota
+	ListFormatMTGO
+	ListFormatCSV
+)
+
+// Detect guesses the format of a deck list from its first non-blank line.
+// Arena exports and plain lists share a parser, so both report ListFormatText.
+func Detect(data []byte) ListFormat {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), bom))
+		if len(line) == 0 {
+			continue
+		}
+		if strings.HasPrefix(line, "<") {
+			return ListFormatMTGO
+		}
+		if isCSVHeader(line) {
+			return ListFormatCSV
+		}
+		return ListFormatText
+	}
+	return ListFormatText
+}
+
+func Parse(r io.Reader) (*Deck, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	switch Detect(data) {
+	case ListFormatMTGO:
+		return ParseMTGO(bytes.NewReader(data))
+	case ListFormatCSV:
+		return ParseCSV(bytes.NewReader(data))
+	default:
+		return ParseText(bytes.NewReader(data))
+	}
+}
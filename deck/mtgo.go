@@ -0,0 +1,70 @@
+package deck
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type mtgoCards struct {
+	CatID     string `xml:"CatID,attr"`
+	Quantity  string `xml:"Quantity,attr"`
+	Sideboard string `xml:"Sideboard,attr"`
+	Name      string `xml:"Name,attr"`
+}
+
+// ParseMTGO parses the .dek XML files written by Magic Online. CatID is the
+// MTGO catalog ID, which Scryfall exposes as mtgo_id.
+func ParseMTGO(r io.Reader) (*Deck, error) {
+	d := &Deck{Entries: []Entry{}}
+	parseErr := &ParseError{}
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Cards" {
+			continue
+		}
+		line, _ := decoder.InputPos()
+		cards := mtgoCards{}
+		err = decoder.DecodeElement(&cards, &start)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := Entry{
+			Line:    line,
+			Section: SectionMain,
+			Name:    strings.TrimSpace(cards.Name),
+		}
+		if strings.EqualFold(cards.Sideboard, "true") {
+			entry.Section = SectionSideboard
+		}
+		entry.Quantity, err = strconv.Atoi(cards.Quantity)
+		if err != nil || entry.Quantity <= 0 {
+			parseErr.add(line, cards.Quantity, ErrInvalidQuantity)
+			continue
+		}
+		if len(cards.CatID) != 0 {
+			entry.MTGOID, err = strconv.Atoi(cards.CatID)
+			if err != nil {
+				parseErr.add(line, cards.CatID, errors.New("invalid CatID"))
+				continue
+			}
+		}
+		if len(entry.Name) == 0 && entry.MTGOID == 0 {
+			parseErr.add(line, "", ErrMissingName)
+			continue
+		}
+		d.Entries = append(d.Entries, entry)
+	}
+	return d, parseErr.err()
+}
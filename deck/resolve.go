@@ -0,0 +1,182 @@
+package deck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tencorvids/scryfall"
+)
+
+type Resolution int
+
+const (
+	ResolutionUnresolved Resolution = iota
+	ResolutionExact
+	ResolutionFuzzy
+)
+
+func (r Resolution) String() string {
+	switch r {
+	case ResolutionExact:
+		return "exact"
+	case ResolutionFuzzy:
+		return "fuzzy"
+	default:
+		return "unresolved"
+	}
+}
+
+type ResolvedEntry struct {
+	Entry      Entry
+	Resolution Resolution
+	Card       *scryfall.Card
+	Err        error
+}
+
+type Report struct {
+	Entries []ResolvedEntry
+}
+
+func (r *Report) Exact() []ResolvedEntry {
+	return r.filter(ResolutionExact)
+}
+
+func (r *Report) Fuzzy() []ResolvedEntry {
+	return r.filter(ResolutionFuzzy)
+}
+
+func (r *Report) Unresolved() []ResolvedEntry {
+	return r.filter(ResolutionUnresolved)
+}
+
+func (r *Report) filter(resolution Resolution) []ResolvedEntry {
+	entries := []ResolvedEntry{}
+	for _, entry := range r.Entries {
+		if entry.Resolution == resolution {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+type Resolver struct {
+	fetcher scryfall.CardFetcher
+}
+
+func NewResolver(fetcher scryfall.CardFetcher) *Resolver {
+	return &Resolver{fetcher: fetcher}
+}
+
+// Resolve looks every entry up through GetCardsByIdentifiers and retries the
+// misses by fuzzy name. A retry that lands on a card whose name or face name
+// equals the entry, and on the printing the entry asked for if it named one,
+// still counts as exact; anything else is a fuzzy correction.
+func (r *Resolver) Resolve(ctx context.Context, d *Deck) (*Report, error) {
+	report := &Report{
+		Entries: make([]ResolvedEntry, len(d.Entries)),
+	}
+	identifiers := make([]scryfall.CardIdentifier, 0, len(d.Entries))
+	for i, entry := range d.Entries {
+		entry.Name = normalizeName(entry.Name)
+		report.Entries[i] = ResolvedEntry{Entry: d.Entries[i]}
+		identifiers = append(identifiers, entry.Identifier())
+	}
+
+	if len(identifiers) != 0 {
+		response, err := r.fetcher.GetCardsByIdentifiers(ctx, identifiers)
+		if err != nil {
+			return nil, err
+		}
+		// DataIndices is what ties each card to its entry. A fetcher that
+		// leaves it out gives us no safe way to attribute the cards.
+		if len(response.DataIndices) != len(response.Data) {
+			return nil, fmt.Errorf("deck: %w: %d cards but %d indices", scryfall.ErrCollectionMismatch, len(response.Data), len(response.DataIndices))
+		}
+		for i, card := range response.Data {
+			index := response.DataIndices[i]
+			if index < 0 || index >= len(report.Entries) {
+				return nil, fmt.Errorf("deck: %w: index %d out of range", scryfall.ErrCollectionMismatch, index)
+			}
+			resolved := &report.Entries[index]
+			resolved.Card = &card
+			resolved.Resolution = ResolutionExact
+		}
+	}
+
+	for i := range report.Entries {
+		resolved := &report.Entries[i]
+		if resolved.Card != nil || len(resolved.Entry.Name) == 0 {
+			continue
+		}
+		card, err := r.fuzzy(ctx, normalizeName(resolved.Entry.Name))
+		if err != nil {
+			if !errors.Is(err, scryfall.ErrNotFound) && !errors.Is(err, scryfall.ErrAmbiguousName) {
+				return nil, err
+			}
+			resolved.Err = err
+			continue
+		}
+		resolved.Card = &card
+		resolved.Resolution = ResolutionFuzzy
+		if matchesName(card, resolved.Entry.Name) && matchesPrinting(card, resolved.Entry) {
+			resolved.Resolution = ResolutionExact
+		}
+	}
+	return report, nil
+}
+
+// fuzzy looks a name up by fuzzy match, falling back to the first face of an
+// "A // B" name since not every printing is named after all of its faces.
+func (r *Resolver) fuzzy(ctx context.Context, name string) (scryfall.Card, error) {
+	card, err := r.fetcher.GetCardByName(ctx, name, false, scryfall.GetCardByNameOptions{})
+	if err == nil {
+		return card, nil
+	}
+	front, _, ok := strings.Cut(name, " // ")
+	if !ok || !errors.Is(err, scryfall.ErrNotFound) {
+		return scryfall.Card{}, err
+	}
+	return r.fetcher.GetCardByName(ctx, front, false, scryfall.GetCardByNameOptions{})
+}
+
+var faceSeparator = regexp.MustCompile(`\s*/{1,2}\s*`)
+
+// normalizeName rewrites "Fire/Ice" and "Fire//Ice" into Scryfall's
+// "Fire // Ice" form.
+func normalizeName(name string) string {
+	return faceSeparator.ReplaceAllString(strings.TrimSpace(name), " // ")
+}
+
+func matchesName(card scryfall.Card, name string) bool {
+	name = normalizeName(name)
+	if strings.EqualFold(card.Name, name) {
+		return true
+	}
+	for _, face := range card.CardFaces {
+		if strings.EqualFold(face.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPrinting reports whether card is the printing entry pinned down by
+// ID, MTGO ID, set or collector number. Entries without one match any card.
+func matchesPrinting(card scryfall.Card, entry Entry) bool {
+	if len(entry.ScryfallID) != 0 && card.ID != entry.ScryfallID {
+		return false
+	}
+	if entry.MTGOID != 0 && (card.MTGOID == nil || *card.MTGOID != entry.MTGOID) {
+		return false
+	}
+	if len(entry.Set) != 0 && !strings.EqualFold(card.Set, entry.Set) {
+		return false
+	}
+	if len(entry.CollectorNumber) != 0 && card.CollectorNumber != entry.CollectorNumber {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package deck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tencorvids/scryfall"
+	"github.com/tencorvids/scryfall/bulk"
+)
+
+func testStore() *bulk.LocalStore {
+	store := bulk.NewLocalStore()
+	store.Add(scryfall.Card{ID: "bolt-m10", OracleID: "bolt", Name: "Lightning Bolt", Set: "m10", CollectorNumber: "146", Lang: scryfall.LangEnglish})
+	store.Add(scryfall.Card{ID: "fire-ice", OracleID: "fire-ice", Name: "Fire // Ice", Set: "mh2", CollectorNumber: "290", Lang: scryfall.LangEnglish, CardFaces: []scryfall.CardFace{{Name: "Fire"}, {Name: "Ice"}}})
+	store.Add(scryfall.Card{ID: "bonecrusher", OracleID: "bonecrusher", Name: "Bonecrusher Giant // Stomp", Set: "eld", CollectorNumber: "115", Lang: scryfall.LangEnglish, CardFaces: []scryfall.CardFace{{Name: "Bonecrusher Giant"}, {Name: "Stomp"}}})
+	return store
+}
+
+func TestResolve(t *testing.T) {
+	d, err := ParseText(strings.NewReader("4 Lightning Bolt (M10) 146\n" +
+		"2 Fire/Ice\n" +
+		"1 Bonecrusher Giant\n" +
+		"1 Bonecrusher Giant // Stomp (ELD) 115\n" +
+		"4 Lightning Bolt (2XM) 129\n" +
+		"1 Nope Nothing\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := NewResolver(testStore()).Resolve(context.Background(), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		resolution Resolution
+		id         string
+	}{
+		{ResolutionExact, "bolt-m10"},
+		{ResolutionExact, "fire-ice"},
+		{ResolutionExact, "bonecrusher"},
+		{ResolutionExact, "bonecrusher"},
+		{ResolutionFuzzy, "bolt-m10"},
+		{ResolutionUnresolved, ""},
+	}
+	for i, w := range want {
+		got := report.Entries[i]
+		id := ""
+		if got.Card != nil {
+			id = got.Card.ID
+		}
+		if got.Resolution != w.resolution || id != w.id {
+			t.Errorf("line %d: got %v %q, want %v %q", got.Entry.Line, got.Resolution, id, w.resolution, w.id)
+		}
+	}
+	if got := len(report.Unresolved()); got != 1 {
+		t.Errorf("got %d unresolved entries, want 1", got)
+	}
+}
+
+// indexlessFetcher stands in for a CardFetcher that does not report which
+// identifier each card answers.
+type indexlessFetcher struct {
+	*bulk.LocalStore
+}
+
+func (f indexlessFetcher) GetCardsByIdentifiers(ctx context.Context, identifiers []scryfall.CardIdentifier) (scryfall.GetCardsByIdentifiersResponse, error) {
+	response, err := f.LocalStore.GetCardsByIdentifiers(ctx, identifiers)
+	response.DataIndices = nil
+	return response, err
+}
+
+func TestResolveWithoutDataIndices(t *testing.T) {
+	d, err := ParseText(strings.NewReader("4 Lightning Bolt\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewResolver(indexlessFetcher{testStore()}).Resolve(context.Background(), d)
+	if !errors.Is(err, scryfall.ErrCollectionMismatch) {
+		t.Errorf("got %v, want ErrCollectionMismatch", err)
+	}
+}
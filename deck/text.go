@@ -0,0 +1,124 @@
+package deck
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	textLinePattern = regexp.MustCompile(`^(\d+)\s*[xX]?\s+(.*)$`)
+	printingPattern = regexp.MustCompile(`^(.*?)\s+\(([A-Za-z0-9]+)\)(?:\s+(\S+))?$`)
+	finishPattern   = regexp.MustCompile(`\s+\*[A-Z]+\*$`)
+)
+
+var sectionHeaders = map[string]Section{
+	"deck":      SectionMain,
+	"main":      SectionMain,
+	"maindeck":  SectionMain,
+	"mainboard": SectionMain,
+	"sideboard": SectionSideboard,
+	"commander": SectionCommander,
+	"companion": SectionCompanion,
+}
+
+// ParseText parses MTG Arena exports and plain "4x Name" lists. Without
+// explicit section headers, a blank line after the main deck starts the
+// sideboard, as in MTGO and most plain text exports.
+func ParseText(r io.Reader) (*Deck, error) {
+	d := &Deck{Entries: []Entry{}}
+	parseErr := &ParseError{}
+	section := SectionMain
+	headers := false
+	about := false
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		text := scanner.Text()
+		line := strings.TrimSpace(text)
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, bom)
+		}
+		if len(line) == 0 {
+			if !headers && section == SectionMain && d.Count(SectionMain) != 0 {
+				section = SectionSideboard
+			}
+			continue
+		}
+
+		header := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(strings.TrimLeft(line, "/#")), ":"))
+		if s, ok := sectionHeaders[header]; ok {
+			section = s
+			headers = true
+			about = false
+			continue
+		}
+		if header == "about" {
+			headers = true
+			about = true
+			continue
+		}
+		if about {
+			name, ok := strings.CutPrefix(line, "Name ")
+			if ok {
+				d.Name = strings.TrimSpace(name)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		entrySection := section
+		if rest, ok := cutFold(line, "SB:"); ok {
+			entrySection = SectionSideboard
+			line = strings.TrimSpace(rest)
+		}
+		entry, err := parseTextLine(line)
+		if err != nil {
+			parseErr.add(lineNumber, text, err)
+			continue
+		}
+		entry.Line = lineNumber
+		entry.Section = entrySection
+		d.Entries = append(d.Entries, entry)
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+	return d, parseErr.err()
+}
+
+func parseTextLine(line string) (Entry, error) {
+	entry := Entry{Quantity: 1}
+	if m := textLinePattern.FindStringSubmatch(line); m != nil {
+		quantity, err := strconv.Atoi(m[1])
+		if err != nil || quantity <= 0 {
+			return Entry{}, ErrInvalidQuantity
+		}
+		entry.Quantity = quantity
+		line = m[2]
+	}
+	line = finishPattern.ReplaceAllString(line, "")
+	if m := printingPattern.FindStringSubmatch(line); m != nil {
+		line = m[1]
+		entry.Set = strings.ToLower(m[2])
+		entry.CollectorNumber = m[3]
+	}
+	entry.Name = strings.TrimSpace(line)
+	if len(entry.Name) == 0 {
+		return Entry{}, ErrMissingName
+	}
+	return entry, nil
+}
+
+func cutFold(s string, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
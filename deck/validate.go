@@ -0,0 +1,247 @@
+package deck
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/tencorvids/scryfall"
+)
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+type ViolationKind string
+
+const (
+	ViolationUnresolved    ViolationKind = "unresolved"
+	ViolationDeckSize      ViolationKind = "deck_size"
+	ViolationSideboardSize ViolationKind = "sideboard_size"
+	ViolationTooManyCopies ViolationKind = "too_many_copies"
+	ViolationNotLegal      ViolationKind = "not_legal"
+	ViolationBanned        ViolationKind = "banned"
+	ViolationRestricted    ViolationKind = "restricted"
+)
+
+type Violation struct {
+	Kind    ViolationKind
+	Message string
+	Cards   []string
+	Lines   []int
+}
+
+func (v Violation) String() string {
+	return v.Message
+}
+
+// FormatRules describes the deck construction limits of a format. A zero
+// MaxDeckSize means the deck has no upper bound.
+type FormatRules struct {
+	MinDeckSize      int
+	MaxDeckSize      int
+	MaxSideboardSize int
+	MaxCopies        int
+}
+
+var constructedRules = FormatRules{MinDeckSize: 60, MaxSideboardSize: 15, MaxCopies: 4}
+
+var singletonRules = FormatRules{MinDeckSize: 100, MaxDeckSize: 100, MaxCopies: 1}
+
+var formatRules = map[scryfall.Format]FormatRules{
+	scryfall.FormatStandard:        constructedRules,
+	scryfall.FormatFuture:          constructedRules,
+	scryfall.FormatHistoric:        constructedRules,
+	scryfall.FormatTimeless:        constructedRules,
+	scryfall.FormatPioneer:         constructedRules,
+	scryfall.FormatExplorer:        constructedRules,
+	scryfall.FormatModern:          constructedRules,
+	scryfall.FormatLegacy:          constructedRules,
+	scryfall.FormatVintage:         constructedRules,
+	scryfall.FormatPauper:          constructedRules,
+	scryfall.FormatPenny:           constructedRules,
+	scryfall.FormatAlchemy:         constructedRules,
+	scryfall.FormatOldSchool:       constructedRules,
+	scryfall.FormatPremodern:       constructedRules,
+	scryfall.FormatGladiator:       singletonRules,
+	scryfall.FormatCommander:       singletonRules,
+	scryfall.FormatDuel:            singletonRules,
+	scryfall.FormatBrawl:           singletonRules,
+	scryfall.FormatPauperCommander: singletonRules,
+	scryfall.FormatPreDH:           singletonRules,
+	scryfall.FormatStandardBrawl:   {MinDeckSize: 60, MaxDeckSize: 60, MaxCopies: 1},
+	scryfall.FormatOathbreaker:     {MinDeckSize: 60, MaxDeckSize: 60, MaxCopies: 1},
+}
+
+func RulesFor(format scryfall.Format) (FormatRules, bool) {
+	rules, ok := formatRules[format]
+	return rules, ok
+}
+
+type cardCount struct {
+	name     string
+	card     *scryfall.Card
+	quantity int
+	lines    []int
+}
+
+// Validate checks a resolved deck against the construction rules and card
+// legalities of format. Unresolved entries count towards deck size but are
+// otherwise reported as violations rather than guessed at.
+func Validate(report *Report, format scryfall.Format) ([]Violation, error) {
+	rules, ok := RulesFor(format)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+	violations := []Violation{}
+
+	sideboardKeys := map[string]bool{}
+	for _, resolved := range report.Entries {
+		if resolved.Entry.Section == SectionSideboard {
+			sideboardKeys[entryKey(resolved)] = true
+		}
+	}
+	deckSize := 0
+	sideboardSize := 0
+	counts := []*cardCount{}
+	byKey := map[string]*cardCount{}
+	for _, resolved := range report.Entries {
+		entry := resolved.Entry
+		switch entry.Section {
+		case SectionSideboard:
+			sideboardSize += entry.Quantity
+		case SectionCompanion:
+			// Arena lists the companion both on its own and in the sideboard.
+			// Formats without a sideboard keep the companion outside the deck.
+			if sideboardKeys[entryKey(resolved)] {
+				continue
+			}
+			if rules.MaxSideboardSize != 0 {
+				sideboardSize += entry.Quantity
+			}
+		default:
+			deckSize += entry.Quantity
+		}
+		if resolved.Card == nil {
+			violations = append(violations, Violation{
+				Kind:    ViolationUnresolved,
+				Message: fmt.Sprintf("%s could not be resolved", entry.Name),
+				Cards:   []string{entry.Name},
+				Lines:   []int{entry.Line},
+			})
+			continue
+		}
+		key := entryKey(resolved)
+		count, ok := byKey[key]
+		if !ok {
+			count = &cardCount{name: resolved.Card.Name, card: resolved.Card}
+			byKey[key] = count
+			counts = append(counts, count)
+		}
+		count.quantity += entry.Quantity
+		count.lines = append(count.lines, entry.Line)
+	}
+
+	if deckSize < rules.MinDeckSize {
+		violations = append(violations, Violation{
+			Kind:    ViolationDeckSize,
+			Message: fmt.Sprintf("deck has %d cards, need at least %d", deckSize, rules.MinDeckSize),
+		})
+	}
+	if rules.MaxDeckSize != 0 && deckSize > rules.MaxDeckSize {
+		violations = append(violations, Violation{
+			Kind:    ViolationDeckSize,
+			Message: fmt.Sprintf("deck has %d cards, at most %d allowed", deckSize, rules.MaxDeckSize),
+		})
+	}
+	if sideboardSize > rules.MaxSideboardSize {
+		violations = append(violations, Violation{
+			Kind:    ViolationSideboardSize,
+			Message: fmt.Sprintf("sideboard has %d cards, at most %d allowed", sideboardSize, rules.MaxSideboardSize),
+		})
+	}
+
+	for _, count := range counts {
+		legality, _ := count.card.Legalities.Get(format)
+		switch legality {
+		case scryfall.LegalityBanned:
+			violations = append(violations, cardViolation(ViolationBanned, count, "%s is banned in %s", count.name, format))
+			continue
+		case scryfall.LegalityRestricted:
+			if count.quantity > 1 {
+				violations = append(violations, cardViolation(ViolationRestricted, count, "%s is restricted in %s, found %d copies", count.name, format, count.quantity))
+			}
+			continue
+		case scryfall.LegalityLegal:
+		default:
+			violations = append(violations, cardViolation(ViolationNotLegal, count, "%s is not legal in %s", count.name, format))
+			continue
+		}
+		limit := copyLimit(count.card, rules.MaxCopies)
+		if limit >= 0 && count.quantity > limit {
+			violations = append(violations, cardViolation(ViolationTooManyCopies, count, "%s has %d copies, at most %d allowed", count.name, count.quantity, limit))
+		}
+	}
+	return violations, nil
+}
+
+func cardViolation(kind ViolationKind, count *cardCount, format string, args ...any) Violation {
+	return Violation{
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+		Cards:   []string{count.name},
+		Lines:   slices.Clone(count.lines),
+	}
+}
+
+func entryKey(resolved ResolvedEntry) string {
+	if resolved.Card != nil && len(resolved.Card.OracleID) != 0 {
+		return resolved.Card.OracleID
+	}
+	if resolved.Card != nil {
+		return strings.ToLower(resolved.Card.Name)
+	}
+	return strings.ToLower(normalizeName(resolved.Entry.Name))
+}
+
+var anyNumberPattern = regexp.MustCompile(`(?i)a deck can have (any number of|up to (\w+)) cards named`)
+
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// copyLimit returns how many copies of card a deck may hold, or -1 when
+// there is no limit. Basic lands and cards such as Relentless Rats lift the
+// format's limit; Seven Dwarves and Nazgûl replace it with their own.
+func copyLimit(card *scryfall.Card, maxCopies int) int {
+	if isBasicLand(card) {
+		return -1
+	}
+	m := anyNumberPattern.FindStringSubmatch(oracleText(card))
+	if m == nil {
+		return maxCopies
+	}
+	if len(m[2]) == 0 {
+		return -1
+	}
+	limit, ok := numberWords[strings.ToLower(m[2])]
+	if !ok {
+		return maxCopies
+	}
+	return limit
+}
+
+func isBasicLand(card *scryfall.Card) bool {
+	typeLine, _, _ := strings.Cut(card.TypeLine, " // ")
+	return strings.Contains(typeLine, "Basic") && strings.Contains(typeLine, "Land")
+}
+
+func oracleText(card *scryfall.Card) string {
+	texts := []string{card.OracleText}
+	for _, face := range card.CardFaces {
+		if face.OracleText != nil {
+			texts = append(texts, *face.OracleText)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
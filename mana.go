@@ -0,0 +1,204 @@
+package scryfall
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidManaCost = errors.New("invalid mana cost")
+
+var colorOrder = []Color{ColorWhite, ColorBlue, ColorBlack, ColorRed, ColorGreen}
+
+type ManaSymbol struct {
+	Symbol    string  `json:"symbol"`
+	CMC       float64 `json:"cmc"`
+	Colors    []Color `json:"colors"`
+	Generic   bool    `json:"generic"`
+	Variable  bool    `json:"variable"`
+	Colorless bool    `json:"colorless"`
+	Snow      bool    `json:"snow"`
+	Hybrid    bool    `json:"hybrid"`
+	Phyrexian bool    `json:"phyrexian"`
+}
+
+func ParseManaSymbols(cost string) ([]ManaSymbol, error) {
+	cost = strings.ToUpper(strings.TrimSpace(cost))
+	symbols := []ManaSymbol{}
+	if !strings.Contains(cost, "{") {
+		// Like the API, accept shorthand such as "2WW" and treat each
+		// character, or each run of digits, as a symbol.
+		for i := 0; i < len(cost); i++ {
+			_, size := utf8.DecodeRuneInString(cost[i:])
+			j := i + size
+			if isDigit(cost[i]) {
+				for j < len(cost) && isDigit(cost[j]) {
+					j++
+				}
+			}
+			symbol, err := parseManaSymbol(cost[i:j])
+			if err != nil {
+				return nil, err
+			}
+			symbols = append(symbols, symbol)
+			i = j - 1
+		}
+		return symbols, nil
+	}
+	rest := cost
+	for len(rest) != 0 {
+		if rest[0] != '{' {
+			return nil, fmt.Errorf("%w: unexpected %q in %q", ErrInvalidManaCost, rest[0], cost)
+		}
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated symbol in %q", ErrInvalidManaCost, cost)
+		}
+		symbol, err := parseManaSymbol(rest[1:end])
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+		rest = rest[end+1:]
+	}
+	return symbols, nil
+}
+
+func ParseManaCostOffline(cost string) (ManaCost, error) {
+	if len(strings.TrimSpace(cost)) == 0 {
+		return ManaCost{}, fmt.Errorf("%w: empty cost", ErrInvalidManaCost)
+	}
+	symbols, err := ParseManaSymbols(cost)
+	if err != nil {
+		return ManaCost{}, err
+	}
+	manaCost := ManaCost{
+		Colors: []Color{},
+	}
+	var b strings.Builder
+	for _, symbol := range symbols {
+		b.WriteString(symbol.Symbol)
+		manaCost.CMC += symbol.CMC
+		for _, color := range symbol.Colors {
+			if !slices.Contains(manaCost.Colors, color) {
+				manaCost.Colors = append(manaCost.Colors, color)
+			}
+		}
+	}
+	slices.SortFunc(manaCost.Colors, func(a Color, b Color) int {
+		return slices.Index(colorOrder, a) - slices.Index(colorOrder, b)
+	})
+	manaCost.Cost = b.String()
+	manaCost.Colorless = len(manaCost.Colors) == 0
+	manaCost.Monocolored = len(manaCost.Colors) == 1
+	manaCost.Multicolored = len(manaCost.Colors) > 1
+	return manaCost, nil
+}
+
+func parseManaSymbol(s string) (ManaSymbol, error) {
+	symbol := ManaSymbol{
+		Symbol: "{" + s + "}",
+		Colors: []Color{},
+	}
+	if s == "P" {
+		symbol.Phyrexian = true
+		symbol.CMC = 1
+		return symbol, nil
+	}
+	parts := strings.Split(s, "/")
+	if len(parts) > 1 && parts[len(parts)-1] == "P" {
+		symbol.Phyrexian = true
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 2 {
+		return ManaSymbol{}, fmt.Errorf("%w: unknown symbol %s", ErrInvalidManaCost, symbol.Symbol)
+	}
+	symbol.Hybrid = len(parts) == 2
+	for _, part := range parts {
+		err := symbol.addPart(part)
+		if err != nil {
+			return ManaSymbol{}, err
+		}
+	}
+	return symbol, nil
+}
+
+// addPart folds one half of a hybrid symbol (or a whole plain symbol) into
+// the symbol. Hybrid symbols cost as much as their most expensive half.
+func (m *ManaSymbol) addPart(part string) error {
+	cmc := 1.0
+	switch part {
+	case "W", "U", "B", "R", "G":
+		m.Colors = append(m.Colors, Color(part))
+	case "HW", "HU", "HB", "HR", "HG":
+		m.Colors = append(m.Colors, Color(part[1:]))
+		cmc = 0.5
+	case "C":
+		m.Colorless = true
+	case "S":
+		m.Snow = true
+	case "X", "Y", "Z":
+		m.Variable = true
+		cmc = 0
+	case "½":
+		m.Generic = true
+		cmc = 0.5
+	default:
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%w: unknown symbol %s", ErrInvalidManaCost, m.Symbol)
+		}
+		m.Generic = true
+		cmc = float64(n)
+	}
+	m.CMC = max(m.CMC, cmc)
+	return nil
+}
+
+func CheckManaSymbols(table []CardSymbol) error {
+	var errs []error
+	for _, cardSymbol := range table {
+		if !cardSymbol.AppearsInManaCosts || cardSymbol.Funny {
+			continue
+		}
+		symbols, err := ParseManaSymbols(cardSymbol.Symbol)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(symbols) != 1 {
+			errs = append(errs, fmt.Errorf("%s: parsed as %d symbols", cardSymbol.Symbol, len(symbols)))
+			continue
+		}
+		symbol := symbols[0]
+		if symbol.CMC != cardSymbol.ManaValue {
+			errs = append(errs, fmt.Errorf("%s: mana value %v, want %v", cardSymbol.Symbol, symbol.CMC, cardSymbol.ManaValue))
+		}
+		if !sameColors(symbol.Colors, cardSymbol.Colors) {
+			errs = append(errs, fmt.Errorf("%s: colors %v, want %v", cardSymbol.Symbol, symbol.Colors, cardSymbol.Colors))
+		}
+		if symbol.Hybrid != cardSymbol.Hybrid || symbol.Phyrexian != cardSymbol.Phyrexian {
+			errs = append(errs, fmt.Errorf("%s: hybrid/phyrexian flags disagree", cardSymbol.Symbol))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sameColors(a []Color, b []Color) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, color := range a {
+		if !slices.Contains(b, color) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,85 @@
+package scryfall
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseManaCostOffline(t *testing.T) {
+	tests := []struct {
+		cost   string
+		want   string
+		cmc    float64
+		colors []Color
+	}{
+		{"{2}{W}{W}", "{2}{W}{W}", 4, []Color{ColorWhite}},
+		{"2ww", "{2}{W}{W}", 4, []Color{ColorWhite}},
+		{"{10}", "{10}", 10, []Color{}},
+		{"{X}{R}{R}", "{X}{R}{R}", 2, []Color{ColorRed}},
+		{"{G}{U}", "{G}{U}", 2, []Color{ColorBlue, ColorGreen}},
+		{"{W/U}", "{W/U}", 1, []Color{ColorWhite, ColorBlue}},
+		{"{2/W}{2/W}", "{2/W}{2/W}", 4, []Color{ColorWhite}},
+		{"{G/W/P}", "{G/W/P}", 1, []Color{ColorWhite, ColorGreen}},
+		{"{B/P}", "{B/P}", 1, []Color{ColorBlack}},
+		{"{HR}", "{HR}", 0.5, []Color{ColorRed}},
+		{"{½}", "{½}", 0.5, []Color{}},
+		{"{C}{S}", "{C}{S}", 2, []Color{}},
+		{" {1}{b} ", "{1}{B}", 2, []Color{ColorBlack}},
+	}
+	for _, test := range tests {
+		manaCost, err := ParseManaCostOffline(test.cost)
+		if err != nil {
+			t.Errorf("%q: %v", test.cost, err)
+			continue
+		}
+		if manaCost.Cost != test.want || manaCost.CMC != test.cmc || !slices.Equal(manaCost.Colors, test.colors) {
+			t.Errorf("%q: got %s, cmc %v, colors %v, want %s, cmc %v, colors %v",
+				test.cost, manaCost.Cost, manaCost.CMC, manaCost.Colors, test.want, test.cmc, test.colors)
+		}
+		if manaCost.Colorless != (len(test.colors) == 0) || manaCost.Monocolored != (len(test.colors) == 1) || manaCost.Multicolored != (len(test.colors) > 1) {
+			t.Errorf("%q: got colorless %t, monocolored %t, multicolored %t", test.cost, manaCost.Colorless, manaCost.Monocolored, manaCost.Multicolored)
+		}
+	}
+}
+
+func TestParseManaCostOfflineInvalid(t *testing.T) {
+	for _, cost := range []string{"", "   ", "{W", "W}", "{Q}", "{W/U/B}", "{-1}", "{2}x{W}"} {
+		_, err := ParseManaCostOffline(cost)
+		if !errors.Is(err, ErrInvalidManaCost) {
+			t.Errorf("%q: got %v, want ErrInvalidManaCost", cost, err)
+		}
+	}
+}
+
+func TestParseManaSymbolFlags(t *testing.T) {
+	symbols, err := ParseManaSymbols("{2/W}{U/P}{X}{S}{C}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ManaSymbol{
+		{Symbol: "{2/W}", CMC: 2, Colors: []Color{ColorWhite}, Generic: true, Hybrid: true},
+		{Symbol: "{U/P}", CMC: 1, Colors: []Color{ColorBlue}, Phyrexian: true},
+		{Symbol: "{X}", Colors: []Color{}, Variable: true},
+		{Symbol: "{S}", CMC: 1, Colors: []Color{}, Snow: true},
+		{Symbol: "{C}", CMC: 1, Colors: []Color{}, Colorless: true},
+	}
+	if len(symbols) != len(want) {
+		t.Fatalf("got %d symbols, want %d", len(symbols), len(want))
+	}
+	for i, symbol := range symbols {
+		w := want[i]
+		if symbol.Symbol != w.Symbol || symbol.CMC != w.CMC || !slices.Equal(symbol.Colors, w.Colors) ||
+			symbol.Generic != w.Generic || symbol.Variable != w.Variable || symbol.Colorless != w.Colorless ||
+			symbol.Snow != w.Snow || symbol.Hybrid != w.Hybrid || symbol.Phyrexian != w.Phyrexian {
+			t.Errorf("got %+v, want %+v", symbol, w)
+		}
+	}
+}
+
+func TestCheckManaSymbolsAgainstDefaultSymbology(t *testing.T) {
+	err := CheckManaSymbols(DefaultSymbology().Symbols())
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,56 @@
+package query
+
+type Op string
+
+const (
+	Colon Op = ":"
+	EQ    Op = "="
+	NE    Op = "!="
+	LT    Op = "<"
+	LTE   Op = "<="
+	GT    Op = ">"
+	GTE   Op = ">="
+)
+
+type Pos struct {
+	Start int
+	End   int
+}
+
+func (p Pos) Position() Pos {
+	return p
+}
+
+type Node interface {
+	Position() Pos
+	node()
+}
+
+type Term struct {
+	Pos
+	Keyword string
+	Op      Op
+	Value   string
+	Regex   bool
+	Exact   bool
+}
+
+type NotExpr struct {
+	Pos
+	X Node
+}
+
+type AndExpr struct {
+	Pos
+	Nodes []Node
+}
+
+type OrExpr struct {
+	Pos
+	Nodes []Node
+}
+
+func (*Term) node()    {}
+func (*NotExpr) node() {}
+func (*AndExpr) node() {}
+func (*OrExpr) node()  {}
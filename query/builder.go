@@ -0,0 +1,257 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tencorvids/scryfall"
+)
+
+type Query struct {
+	nodes []Node
+}
+
+func New() Query {
+	return Query{}
+}
+
+func (q Query) Node() Node {
+	if len(q.nodes) == 1 {
+		return q.nodes[0]
+	}
+	return &AndExpr{Nodes: q.nodes}
+}
+
+func (q Query) String() string {
+	return Print(q.Node())
+}
+
+func (q Query) with(n Node) Query {
+	nodes := make([]Node, len(q.nodes), len(q.nodes)+1)
+	copy(nodes, q.nodes)
+	return Query{nodes: append(nodes, n)}
+}
+
+func (q Query) term(keyword string, op Op, value string) Query {
+	return q.with(&Term{Keyword: keyword, Op: op, Value: value})
+}
+
+func colorValue(colors []scryfall.Color) string {
+	if len(colors) == 0 {
+		return "c"
+	}
+	var b strings.Builder
+	for _, color := range colors {
+		b.WriteString(strings.ToLower(string(color)))
+	}
+	return b.String()
+}
+
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func (q Query) Name(name string) Query {
+	return q.with(&Term{Value: name})
+}
+
+func (q Query) ExactName(name string) Query {
+	return q.with(&Term{Value: name, Exact: true})
+}
+
+func (q Query) Color(colors ...scryfall.Color) Query {
+	return q.term("c", GTE, colorValue(colors))
+}
+
+func (q Query) ColorOp(op Op, colors ...scryfall.Color) Query {
+	return q.term("c", op, colorValue(colors))
+}
+
+func (q Query) ColorIdentity(op Op, colors ...scryfall.Color) Query {
+	return q.term("id", op, colorValue(colors))
+}
+
+func (q Query) CMC(op Op, v float64) Query {
+	return q.term("cmc", op, number(v))
+}
+
+func (q Query) Power(op Op, v string) Query {
+	return q.term("pow", op, v)
+}
+
+func (q Query) Toughness(op Op, v string) Query {
+	return q.term("tou", op, v)
+}
+
+func (q Query) Loyalty(op Op, v string) Query {
+	return q.term("loy", op, v)
+}
+
+func (q Query) Type(typ string) Query {
+	return q.term("t", Colon, typ)
+}
+
+func (q Query) Oracle(text string) Query {
+	return q.term("o", Colon, text)
+}
+
+func (q Query) OracleRegex(pattern string) Query {
+	return q.with(&Term{Keyword: "o", Op: Colon, Value: pattern, Regex: true})
+}
+
+func (q Query) Keyword(keyword string) Query {
+	return q.term("kw", Colon, keyword)
+}
+
+func (q Query) Rarity(op Op, rarity scryfall.Rarity) Query {
+	return q.term("r", op, string(rarity))
+}
+
+func (q Query) Set(code string) Query {
+	return q.term("s", Colon, code)
+}
+
+func (q Query) Artist(name string) Query {
+	return q.term("a", Colon, name)
+}
+
+func (q Query) Format(format scryfall.Format, legality scryfall.Legality) Query {
+	switch legality {
+	case scryfall.LegalityBanned:
+		return q.term("banned", Colon, string(format))
+	case scryfall.LegalityRestricted:
+		return q.term("restricted", Colon, string(format))
+	case scryfall.LegalityNotLegal:
+		return q.with(&NotExpr{X: &Term{Keyword: "f", Op: Colon, Value: string(format)}})
+	}
+	return q.term("f", Colon, string(format))
+}
+
+// Layout uses the is: predicates, which is how Scryfall exposes layouts.
+func (q Query) Layout(layout scryfall.Layout) Query {
+	if layout == scryfall.LayoutModalDFC {
+		return q.Is("mdfc")
+	}
+	return q.Is(string(layout))
+}
+
+func (q Query) Frame(frame scryfall.Frame) Query {
+	return q.term("frame", Colon, string(frame))
+}
+
+func (q Query) FrameEffect(effect scryfall.FrameEffect) Query {
+	return q.term("frame", Colon, string(effect))
+}
+
+func (q Query) Lang(lang scryfall.Lang) Query {
+	return q.term("lang", Colon, string(lang))
+}
+
+func (q Query) Is(predicate string) Query {
+	return q.term("is", Colon, predicate)
+}
+
+func (q Query) USD(op Op, v float64) Query {
+	return q.term("usd", op, number(v))
+}
+
+func (q Query) EUR(op Op, v float64) Query {
+	return q.term("eur", op, number(v))
+}
+
+func (q Query) Tix(op Op, v float64) Query {
+	return q.term("tix", op, number(v))
+}
+
+func (q Query) Year(op Op, year int) Query {
+	return q.term("year", op, strconv.Itoa(year))
+}
+
+// Not, And and Or skip empty queries, which would otherwise print as an
+// empty group.
+func (q Query) Not(other Query) Query {
+	if len(other.nodes) == 0 {
+		return q
+	}
+	return q.with(&NotExpr{X: other.Node()})
+}
+
+func (q Query) And(others ...Query) Query {
+	for _, other := range others {
+		if len(other.nodes) != 0 {
+			q = q.with(other.Node())
+		}
+	}
+	return q
+}
+
+func (q Query) Or(others ...Query) Query {
+	or := &OrExpr{}
+	if len(q.nodes) != 0 {
+		or.Nodes = append(or.Nodes, q.Node())
+	}
+	for _, other := range others {
+		if len(other.nodes) != 0 {
+			or.Nodes = append(or.Nodes, other.Node())
+		}
+	}
+	switch len(or.Nodes) {
+	case 0:
+		return q
+	case 1:
+		return Query{nodes: or.Nodes}
+	}
+	return Query{nodes: []Node{or}}
+}
+
+func Name(name string) Query {
+	return New().Name(name)
+}
+
+func ExactName(name string) Query {
+	return New().ExactName(name)
+}
+
+func Color(colors ...scryfall.Color) Query {
+	return New().Color(colors...)
+}
+
+func ColorIdentity(op Op, colors ...scryfall.Color) Query {
+	return New().ColorIdentity(op, colors...)
+}
+
+func CMC(op Op, v float64) Query {
+	return New().CMC(op, v)
+}
+
+func Type(typ string) Query {
+	return New().Type(typ)
+}
+
+func Oracle(text string) Query {
+	return New().Oracle(text)
+}
+
+func Rarity(op Op, rarity scryfall.Rarity) Query {
+	return New().Rarity(op, rarity)
+}
+
+func Set(code string) Query {
+	return New().Set(code)
+}
+
+func Format(format scryfall.Format, legality scryfall.Legality) Query {
+	return New().Format(format, legality)
+}
+
+func Is(predicate string) Query {
+	return New().Is(predicate)
+}
+
+func Not(q Query) Query {
+	return New().Not(q)
+}
+
+func Or(qs ...Query) Query {
+	return New().Or(qs...)
+}
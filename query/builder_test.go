@@ -0,0 +1,45 @@
+package query
+
+import "testing"
+
+func TestBuilderSkipsEmptyQueries(t *testing.T) {
+	tests := []struct {
+		q    Query
+		want string
+	}{
+		{New(), ""},
+		{Not(New()), ""},
+		{Type("goblin").Not(New()), "t:goblin"},
+		{Or(), ""},
+		{Or(New(), New()), ""},
+		{Or(Type("goblin"), New()), "t:goblin"},
+		{Type("goblin").Or(New()), "t:goblin"},
+		{New().And(New(), Type("goblin")), "t:goblin"},
+		{Type("goblin").Or(Type("elf")), "t:goblin or t:elf"},
+		{Not(Type("goblin").Or(Type("elf"))), "-(t:goblin or t:elf)"},
+		{Set("m10").Not(Or(New())), "s:m10"},
+	}
+	for _, test := range tests {
+		if got := test.q.String(); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestPrintEmptyGroups(t *testing.T) {
+	goblin := &Term{Keyword: "t", Op: Colon, Value: "goblin"}
+	tests := []struct {
+		n    Node
+		want string
+	}{
+		{&NotExpr{X: &AndExpr{}}, ""},
+		{&OrExpr{Nodes: []Node{&AndExpr{}, &OrExpr{}}}, ""},
+		{&AndExpr{Nodes: []Node{goblin, &NotExpr{X: &OrExpr{}}}}, "t:goblin"},
+		{&NotExpr{X: &OrExpr{Nodes: []Node{goblin, &AndExpr{}}}}, "-t:goblin"},
+	}
+	for _, test := range tests {
+		if got := Print(test.n); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,773 @@
+package query
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tencorvids/scryfall"
+)
+
+type UnsupportedError struct {
+	Terms []*Term
+}
+
+func (e *UnsupportedError) Error() string {
+	parts := make([]string, 0, len(e.Terms))
+	for _, term := range e.Terms {
+		parts = append(parts, fmt.Sprintf("%s (offset %d)", Print(term), term.Start))
+	}
+	return "query: unsupported terms: " + strings.Join(parts, ", ")
+}
+
+type matchFunc func(card *scryfall.Card) bool
+
+type Matcher struct {
+	match matchFunc
+}
+
+func Compile(n Node) (*Matcher, error) {
+	c := &compiler{}
+	match := c.compile(n)
+	if len(c.unsupported) != 0 {
+		return nil, &UnsupportedError{Terms: c.unsupported}
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &Matcher{match: match}, nil
+}
+
+func CompileString(src string) (*Matcher, error) {
+	n, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return Compile(n)
+}
+
+func (m *Matcher) Match(card scryfall.Card) bool {
+	return m.match(&card)
+}
+
+func (m *Matcher) Filter(cards []scryfall.Card) []scryfall.Card {
+	filtered := []scryfall.Card{}
+	for i := range cards {
+		if m.match(&cards[i]) {
+			filtered = append(filtered, cards[i])
+		}
+	}
+	return filtered
+}
+
+type compiler struct {
+	unsupported []*Term
+	err         error
+}
+
+func (c *compiler) compile(n Node) matchFunc {
+	switch n := n.(type) {
+	case *NotExpr:
+		x := c.compile(n.X)
+		return func(card *scryfall.Card) bool {
+			return !x(card)
+		}
+	case *AndExpr:
+		nodes := c.compileAll(n.Nodes)
+		return func(card *scryfall.Card) bool {
+			for _, node := range nodes {
+				if !node(card) {
+					return false
+				}
+			}
+			return true
+		}
+	case *OrExpr:
+		nodes := c.compileAll(n.Nodes)
+		return func(card *scryfall.Card) bool {
+			for _, node := range nodes {
+				if node(card) {
+					return true
+				}
+			}
+			return false
+		}
+	case *Term:
+		match := c.compileTerm(n)
+		if match == nil {
+			c.unsupported = append(c.unsupported, n)
+		}
+		return match
+	}
+	c.err = fmt.Errorf("query: unknown node %T", n)
+	return nil
+}
+
+func (c *compiler) compileAll(nodes []Node) []matchFunc {
+	funcs := make([]matchFunc, 0, len(nodes))
+	for _, node := range nodes {
+		funcs = append(funcs, c.compile(node))
+	}
+	return funcs
+}
+
+// compileTerm returns nil for terms the evaluator cannot answer so that the
+// caller can report them instead of treating them as matches or misses.
+func (c *compiler) compileTerm(t *Term) matchFunc {
+	if len(t.Keyword) == 0 {
+		return c.compileName(t)
+	}
+	switch t.Keyword {
+	case "c":
+		return compileColors(t, cardColors, Colon)
+	case "id":
+		return compileColors(t, func(card *scryfall.Card) []scryfall.Color { return card.ColorIdentity }, LTE)
+	case "t":
+		return c.compileText(t, cardTypeLines)
+	case "o", "fo":
+		return c.compileText(t, cardOracleTexts)
+	case "ft":
+		return c.compileText(t, cardFlavorTexts)
+	case "a":
+		return c.compileText(t, cardArtists)
+	case "kw":
+		if t.Op != Colon || t.Regex {
+			return nil
+		}
+		return func(card *scryfall.Card) bool {
+			return slices.ContainsFunc(card.Keywords, func(keyword string) bool {
+				return strings.EqualFold(keyword, t.Value)
+			})
+		}
+	case "m":
+		return compileManaCost(t)
+	case "cmc":
+		v, err := strconv.ParseFloat(t.Value, 64)
+		if err != nil {
+			return nil
+		}
+		return func(card *scryfall.Card) bool {
+			return compareFloat(t.Op, card.CMC, v)
+		}
+	case "pow":
+		return compileStat(t, func(card *scryfall.Card) []*string {
+			return cardStats(card, func(face *scryfall.CardFace) *string { return face.Power }, card.Power)
+		})
+	case "tou":
+		return compileStat(t, func(card *scryfall.Card) []*string {
+			return cardStats(card, func(face *scryfall.CardFace) *string { return face.Toughness }, card.Toughness)
+		})
+	case "loy":
+		return compileStat(t, func(card *scryfall.Card) []*string {
+			return cardStats(card, func(face *scryfall.CardFace) *string { return face.Loyalty }, card.Loyalty)
+		})
+	case "r":
+		return compileRarity(t)
+	case "s":
+		return compileEqualFold(t, func(card *scryfall.Card) string { return card.Set })
+	case "cn":
+		return compileCollectorNumber(t)
+	case "lang":
+		return compileEqualFold(t, func(card *scryfall.Card) string { return string(card.Lang) })
+	case "border":
+		return compileEqualFold(t, func(card *scryfall.Card) string { return card.BorderColor })
+	case "wm":
+		return compileEqualFold(t, func(card *scryfall.Card) string {
+			if card.Watermark == nil {
+				return ""
+			}
+			return *card.Watermark
+		})
+	case "game":
+		if t.Op != Colon {
+			return nil
+		}
+		return func(card *scryfall.Card) bool {
+			return slices.ContainsFunc(card.Games, func(game string) bool {
+				return strings.EqualFold(game, t.Value)
+			})
+		}
+	case "frame":
+		if t.Op != Colon {
+			return nil
+		}
+		return func(card *scryfall.Card) bool {
+			if strings.EqualFold(string(card.Frame), t.Value) {
+				return true
+			}
+			return slices.ContainsFunc(card.FrameEffects, func(effect scryfall.FrameEffect) bool {
+				return strings.EqualFold(string(effect), t.Value)
+			})
+		}
+	case "f", "banned", "restricted":
+		return compileLegality(t)
+	case "usd", "eur", "tix":
+		return compilePrice(t)
+	case "year":
+		year, err := strconv.Atoi(t.Value)
+		if err != nil {
+			return nil
+		}
+		return func(card *scryfall.Card) bool {
+			return !card.ReleasedAt.IsZero() && compareFloat(t.Op, float64(card.ReleasedAt.Year()), float64(year))
+		}
+	case "date":
+		date, err := time.Parse("2006-01-02", t.Value)
+		if err != nil {
+			return nil
+		}
+		return func(card *scryfall.Card) bool {
+			if card.ReleasedAt.IsZero() {
+				return false
+			}
+			released := time.Date(card.ReleasedAt.Year(), card.ReleasedAt.Month(), card.ReleasedAt.Day(), 0, 0, 0, 0, time.UTC)
+			return compareInt(t.Op, released.Compare(date))
+		}
+	case "is":
+		if t.Op != Colon {
+			return nil
+		}
+		return isPredicates[strings.ToLower(t.Value)]
+	case "not":
+		if t.Op != Colon {
+			return nil
+		}
+		predicate := isPredicates[strings.ToLower(t.Value)]
+		if predicate == nil {
+			return nil
+		}
+		return func(card *scryfall.Card) bool {
+			return !predicate(card)
+		}
+	}
+	return nil
+}
+
+func (c *compiler) compileName(t *Term) matchFunc {
+	value := strings.ToLower(t.Value)
+	if t.Exact {
+		return func(card *scryfall.Card) bool {
+			return slices.ContainsFunc(cardNames(card), func(name string) bool {
+				return strings.ToLower(name) == value
+			})
+		}
+	}
+	return func(card *scryfall.Card) bool {
+		return strings.Contains(strings.ToLower(card.Name), value)
+	}
+}
+
+func (c *compiler) compileText(t *Term, texts func(card *scryfall.Card) []string) matchFunc {
+	if t.Op != Colon && t.Op != EQ {
+		return nil
+	}
+	if t.Regex {
+		re, err := regexp.Compile("(?i)" + t.Value)
+		if err != nil {
+			c.err = &SyntaxError{Offset: t.Start, Msg: err.Error()}
+			return func(card *scryfall.Card) bool { return false }
+		}
+		return func(card *scryfall.Card) bool {
+			for _, text := range texts(card) {
+				if re.MatchString(text) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	value := strings.ToLower(t.Value)
+	return func(card *scryfall.Card) bool {
+		needle := strings.ReplaceAll(value, "~", strings.ToLower(card.Name))
+		for _, text := range texts(card) {
+			if strings.Contains(strings.ToLower(text), needle) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func cardNames(card *scryfall.Card) []string {
+	names := []string{card.Name}
+	for _, face := range card.CardFaces {
+		names = append(names, face.Name)
+	}
+	return names
+}
+
+func cardColors(card *scryfall.Card) []scryfall.Color {
+	if card.Colors != nil || len(card.CardFaces) == 0 {
+		return card.Colors
+	}
+	colors := []scryfall.Color{}
+	for _, face := range card.CardFaces {
+		for _, color := range face.Colors {
+			if !slices.Contains(colors, color) {
+				colors = append(colors, color)
+			}
+		}
+	}
+	return colors
+}
+
+func cardTypeLines(card *scryfall.Card) []string {
+	return []string{card.TypeLine}
+}
+
+func cardOracleTexts(card *scryfall.Card) []string {
+	texts := []string{card.OracleText}
+	for _, face := range card.CardFaces {
+		if face.OracleText != nil {
+			texts = append(texts, *face.OracleText)
+		}
+	}
+	return texts
+}
+
+func cardFlavorTexts(card *scryfall.Card) []string {
+	texts := []string{}
+	if card.FlavorText != nil {
+		texts = append(texts, *card.FlavorText)
+	}
+	for _, face := range card.CardFaces {
+		if face.FlavorText != nil {
+			texts = append(texts, *face.FlavorText)
+		}
+	}
+	return texts
+}
+
+func cardArtists(card *scryfall.Card) []string {
+	artists := []string{}
+	if card.Artist != nil {
+		artists = append(artists, *card.Artist)
+	}
+	for _, face := range card.CardFaces {
+		if face.Artist != nil {
+			artists = append(artists, *face.Artist)
+		}
+	}
+	return artists
+}
+
+func cardStats(card *scryfall.Card, faceStat func(face *scryfall.CardFace) *string, stat *string) []*string {
+	stats := []*string{stat}
+	for i := range card.CardFaces {
+		stats = append(stats, faceStat(&card.CardFaces[i]))
+	}
+	return stats
+}
+
+var colorNames = map[string]string{
+	"white":      "w",
+	"blue":       "u",
+	"black":      "b",
+	"red":        "r",
+	"green":      "g",
+	"colorless":  "c",
+	"azorius":    "wu",
+	"dimir":      "ub",
+	"rakdos":     "br",
+	"gruul":      "rg",
+	"selesnya":   "gw",
+	"orzhov":     "wb",
+	"izzet":      "ur",
+	"golgari":    "bg",
+	"boros":      "rw",
+	"simic":      "gu",
+	"bant":       "gwu",
+	"esper":      "wub",
+	"grixis":     "ubr",
+	"jund":       "brg",
+	"naya":       "rgw",
+	"abzan":      "wbg",
+	"jeskai":     "urw",
+	"sultai":     "bgu",
+	"mardu":      "rwb",
+	"temur":      "gur",
+	"wubrg":      "wubrg",
+	"fivecolor":  "wubrg",
+	"multicolor": "m",
+}
+
+func parseColors(value string) ([]scryfall.Color, bool, bool) {
+	value = strings.ToLower(value)
+	if name, ok := colorNames[value]; ok {
+		value = name
+	}
+	if value == "m" {
+		return nil, true, true
+	}
+	colors := []scryfall.Color{}
+	for _, r := range value {
+		switch r {
+		case 'w', 'u', 'b', 'r', 'g':
+			color := scryfall.Color(strings.ToUpper(string(r)))
+			if !slices.Contains(colors, color) {
+				colors = append(colors, color)
+			}
+		case 'c':
+		default:
+			return nil, false, false
+		}
+	}
+	return colors, false, true
+}
+
+func compileColors(t *Term, colorsOf func(card *scryfall.Card) []scryfall.Color, colonOp Op) matchFunc {
+	if t.Regex {
+		return nil
+	}
+	op := t.Op
+	if op == Colon {
+		op = colonOp
+	}
+	if n, err := strconv.Atoi(t.Value); err == nil {
+		return func(card *scryfall.Card) bool {
+			return compareFloat(op, float64(len(colorsOf(card))), float64(n))
+		}
+	}
+	want, multicolor, ok := parseColors(t.Value)
+	if !ok {
+		return nil
+	}
+	if multicolor {
+		switch t.Op {
+		case Colon, EQ:
+			return func(card *scryfall.Card) bool {
+				return len(colorsOf(card)) > 1
+			}
+		case NE:
+			return func(card *scryfall.Card) bool {
+				return len(colorsOf(card)) <= 1
+			}
+		}
+		return nil
+	}
+	// Colorless is not a color to contain, so c:c asks for no colors at all
+	// rather than a superset of the empty set.
+	if len(want) == 0 && (t.Op == Colon || t.Op == EQ) {
+		return func(card *scryfall.Card) bool {
+			return len(colorsOf(card)) == 0
+		}
+	}
+	if op == Colon {
+		op = GTE
+	}
+	return func(card *scryfall.Card) bool {
+		have := colorsOf(card)
+		sub := isSubset(have, want)
+		super := isSubset(want, have)
+		switch op {
+		case EQ:
+			return sub && super
+		case NE:
+			return !(sub && super)
+		case LTE:
+			return sub
+		case LT:
+			return sub && !super
+		case GTE:
+			return super
+		case GT:
+			return super && !sub
+		}
+		return false
+	}
+}
+
+func isSubset(a []scryfall.Color, b []scryfall.Color) bool {
+	for _, color := range a {
+		if !slices.Contains(b, color) {
+			return false
+		}
+	}
+	return true
+}
+
+// compileManaCost compares costs as multisets of symbols, so m:rr matches
+// {R}{G}{R} and m:{U}{G} matches {G}{U}. Generic mana is compared by amount:
+// m:2 asks for at least two generic mana.
+func compileManaCost(t *Term) matchFunc {
+	if t.Op != Colon && t.Op != EQ || t.Regex {
+		return nil
+	}
+	want, err := countManaSymbols(t.Value)
+	if err != nil || len(want.symbols) == 0 && want.generic == 0 {
+		return nil
+	}
+	return func(card *scryfall.Card) bool {
+		costs := []string{card.ManaCost}
+		for _, face := range card.CardFaces {
+			costs = append(costs, face.ManaCost)
+		}
+		for _, cost := range costs {
+			// Split and modal cards join their faces' costs with " // ",
+			// which does not parse; the faces are checked on their own.
+			have, err := countManaSymbols(cost)
+			if err != nil {
+				continue
+			}
+			if t.Op == EQ && have.equal(want) || t.Op == Colon && have.contains(want) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type manaSymbolCount struct {
+	generic float64
+	symbols map[string]int
+}
+
+func countManaSymbols(cost string) (manaSymbolCount, error) {
+	symbols, err := scryfall.ParseManaSymbols(cost)
+	if err != nil {
+		return manaSymbolCount{}, err
+	}
+	count := manaSymbolCount{
+		symbols: map[string]int{},
+	}
+	for _, symbol := range symbols {
+		if symbol.Generic && !symbol.Hybrid {
+			count.generic += symbol.CMC
+			continue
+		}
+		count.symbols[symbol.Symbol]++
+	}
+	return count, nil
+}
+
+func (c manaSymbolCount) contains(other manaSymbolCount) bool {
+	if c.generic < other.generic {
+		return false
+	}
+	for symbol, n := range other.symbols {
+		if c.symbols[symbol] < n {
+			return false
+		}
+	}
+	return true
+}
+
+func (c manaSymbolCount) equal(other manaSymbolCount) bool {
+	return c.generic == other.generic && maps.Equal(c.symbols, other.symbols)
+}
+
+func compileStat(t *Term, stats func(card *scryfall.Card) []*string) matchFunc {
+	v, err := strconv.ParseFloat(t.Value, 64)
+	if err != nil || t.Regex {
+		return nil
+	}
+	return func(card *scryfall.Card) bool {
+		for _, stat := range stats(card) {
+			if stat == nil {
+				continue
+			}
+			n, err := strconv.ParseFloat(*stat, 64)
+			if err == nil && compareFloat(t.Op, n, v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var rarityOrder = map[scryfall.Rarity]int{
+	scryfall.RarityCommon:   0,
+	scryfall.RarityUncommon: 1,
+	scryfall.RarityRare:     2,
+	scryfall.RaritySpecial:  3,
+	scryfall.RarityMythic:   4,
+	scryfall.RarityBonus:    5,
+}
+
+var rarityAliases = map[string]scryfall.Rarity{
+	"c": scryfall.RarityCommon,
+	"u": scryfall.RarityUncommon,
+	"r": scryfall.RarityRare,
+	"s": scryfall.RaritySpecial,
+	"m": scryfall.RarityMythic,
+	"b": scryfall.RarityBonus,
+}
+
+func compileRarity(t *Term) matchFunc {
+	value := strings.ToLower(t.Value)
+	rarity := scryfall.Rarity(value)
+	if alias, ok := rarityAliases[value]; ok {
+		rarity = alias
+	}
+	want, ok := rarityOrder[rarity]
+	if !ok || t.Regex {
+		return nil
+	}
+	return func(card *scryfall.Card) bool {
+		have, ok := rarityOrder[scryfall.Rarity(card.Rarity)]
+		return ok && compareFloat(t.Op, float64(have), float64(want))
+	}
+}
+
+func compileEqualFold(t *Term, field func(card *scryfall.Card) string) matchFunc {
+	if t.Op != Colon && t.Op != EQ && t.Op != NE || t.Regex {
+		return nil
+	}
+	return func(card *scryfall.Card) bool {
+		return strings.EqualFold(field(card), t.Value) != (t.Op == NE)
+	}
+}
+
+func compileCollectorNumber(t *Term) matchFunc {
+	if t.Regex {
+		return nil
+	}
+	v, err := strconv.ParseFloat(t.Value, 64)
+	if err != nil {
+		return compileEqualFold(t, func(card *scryfall.Card) string { return card.CollectorNumber })
+	}
+	return func(card *scryfall.Card) bool {
+		n, err := strconv.ParseFloat(strings.TrimRight(card.CollectorNumber, "abcdefghijklmnopqrstuvwxyz★†"), 64)
+		return err == nil && compareFloat(t.Op, n, v)
+	}
+}
+
+func compileLegality(t *Term) matchFunc {
+	if t.Op != Colon || t.Regex {
+		return nil
+	}
+	format, ok := parseFormat(t.Value)
+	if !ok {
+		return nil
+	}
+	return func(card *scryfall.Card) bool {
+		status, _ := card.Legalities.Get(format)
+		switch t.Keyword {
+		case "banned":
+			return status == scryfall.LegalityBanned
+		case "restricted":
+			return status == scryfall.LegalityRestricted
+		}
+		return card.Legalities.IsLegal(format)
+	}
+}
+
+// formatAliases maps the shorthand Scryfall accepts in f: terms onto the
+// format names used in card legalities.
+var formatAliases = map[string]scryfall.Format{
+	"edh": scryfall.FormatCommander,
+	"pdh": scryfall.FormatPauperCommander,
+}
+
+// parseFormat accepts formats the library has no constant for, so f: terms
+// keep working for formats Scryfall adds later. A card without the format in
+// its legalities simply does not match.
+func parseFormat(value string) (scryfall.Format, bool) {
+	format := scryfall.Format(strings.ToLower(value))
+	if alias, ok := formatAliases[string(format)]; ok {
+		return alias, true
+	}
+	return format, len(format) != 0
+}
+
+func compilePrice(t *Term) matchFunc {
+	v, err := strconv.ParseFloat(t.Value, 64)
+	if err != nil || t.Regex {
+		return nil
+	}
+	return func(card *scryfall.Card) bool {
+		var price string
+		switch t.Keyword {
+		case "usd":
+			price = card.Prices.USD
+		case "eur":
+			price = card.Prices.EUR
+		case "tix":
+			price = card.Prices.Tix
+		}
+		n, err := strconv.ParseFloat(price, 64)
+		return err == nil && compareFloat(t.Op, n, v)
+	}
+}
+
+func compareFloat(op Op, a float64, b float64) bool {
+	switch op {
+	case Colon, EQ:
+		return a == b
+	case NE:
+		return a != b
+	case LT:
+		return a < b
+	case LTE:
+		return a <= b
+	case GT:
+		return a > b
+	case GTE:
+		return a >= b
+	}
+	return false
+}
+
+func compareInt(op Op, cmp int) bool {
+	return compareFloat(op, float64(cmp), 0)
+}
+
+func hasLayout(layouts ...scryfall.Layout) matchFunc {
+	return func(card *scryfall.Card) bool {
+		return slices.Contains(layouts, card.Layout)
+	}
+}
+
+func typeLineContains(words ...string) matchFunc {
+	return func(card *scryfall.Card) bool {
+		typeLine := strings.ToLower(card.TypeLine)
+		for _, word := range words {
+			if strings.Contains(typeLine, word) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var isPredicates = map[string]matchFunc{
+	"reserved":  func(card *scryfall.Card) bool { return card.Reserved },
+	"promo":     func(card *scryfall.Card) bool { return card.Promo },
+	"digital":   func(card *scryfall.Card) bool { return card.Digital },
+	"reprint":   func(card *scryfall.Card) bool { return card.Reprint },
+	"foil":      func(card *scryfall.Card) bool { return card.Foil },
+	"nonfoil":   func(card *scryfall.Card) bool { return card.NonFoil },
+	"fullart":   func(card *scryfall.Card) bool { return card.FullArt },
+	"oversized": func(card *scryfall.Card) bool { return card.Oversized },
+	"booster":   func(card *scryfall.Card) bool { return card.Booster },
+	"hires":     func(card *scryfall.Card) bool { return card.HighresImage },
+	"split":     hasLayout(scryfall.LayoutSplit),
+	"flip":      hasLayout(scryfall.LayoutFlip),
+	"transform": hasLayout(scryfall.LayoutTransform),
+	"meld":      hasLayout(scryfall.LayoutMeld),
+	"leveler":   hasLayout(scryfall.LayoutLeveler),
+	"saga":      hasLayout(scryfall.LayoutSaga),
+	"adventure": hasLayout(scryfall.LayoutAdventure),
+	"mdfc":      hasLayout(scryfall.LayoutModalDFC),
+	"dfc":       hasLayout(scryfall.LayoutTransform, scryfall.LayoutModalDFC, scryfall.LayoutMeld, scryfall.LayoutDoubleFacedToken, scryfall.LayoutReversible),
+	"token":     hasLayout(scryfall.LayoutToken, scryfall.LayoutDoubleFacedToken),
+	"permanent": typeLineContains("artifact", "creature", "enchantment", "land", "planeswalker", "battle"),
+	"spell":     typeLineContains("artifact", "creature", "enchantment", "instant", "sorcery", "planeswalker", "battle"),
+	"historic":  typeLineContains("artifact", "legendary", "saga"),
+	"vanilla": func(card *scryfall.Card) bool {
+		return strings.Contains(strings.ToLower(card.TypeLine), "creature") && len(card.OracleText) == 0 && len(card.CardFaces) == 0
+	},
+	"commander": func(card *scryfall.Card) bool {
+		typeLine := strings.ToLower(card.TypeLine)
+		if strings.Contains(typeLine, "legendary") && strings.Contains(typeLine, "creature") {
+			return true
+		}
+		return slices.ContainsFunc(cardOracleTexts(card), func(text string) bool {
+			return strings.Contains(text, "can be your commander")
+		})
+	},
+}
@@ -0,0 +1,152 @@
+package query
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/tencorvids/scryfall"
+)
+
+func TestMatchColors(t *testing.T) {
+	bolt := scryfall.Card{Name: "Lightning Bolt", Colors: []scryfall.Color{"R"}, ColorIdentity: []scryfall.Color{"R"}}
+	solRing := scryfall.Card{Name: "Sol Ring", Colors: []scryfall.Color{}, ColorIdentity: []scryfall.Color{}}
+	fireIce := scryfall.Card{Name: "Fire // Ice", Colors: []scryfall.Color{"U", "R"}, ColorIdentity: []scryfall.Color{"U", "R"}}
+	cards := []scryfall.Card{bolt, solRing, fireIce}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"c:r", []string{"Lightning Bolt", "Fire // Ice"}},
+		{"c=r", []string{"Lightning Bolt"}},
+		{"c:c", []string{"Sol Ring"}},
+		{"c=c", []string{"Sol Ring"}},
+		{"c:colorless", []string{"Sol Ring"}},
+		{"c!=c", []string{"Lightning Bolt", "Fire // Ice"}},
+		{"c:m", []string{"Fire // Ice"}},
+		{"c=m", []string{"Fire // Ice"}},
+		{"c!=m", []string{"Lightning Bolt", "Sol Ring"}},
+		{"id:c", []string{"Sol Ring"}},
+		{"id:r", []string{"Lightning Bolt", "Sol Ring"}},
+		{"c>=2", []string{"Fire // Ice"}},
+	}
+	for _, test := range tests {
+		m, err := CompileString(test.query)
+		if err != nil {
+			t.Errorf("CompileString(%q): %v", test.query, err)
+			continue
+		}
+		got := []string{}
+		for _, card := range cards {
+			if m.Match(card) {
+				got = append(got, card.Name)
+			}
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%s matched %v, want %v", test.query, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s matched %v, want %v", test.query, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestCompileUnsupportedMulticolorOp(t *testing.T) {
+	_, err := CompileString("c<m")
+	if err == nil {
+		t.Fatal("c<m compiled, want an unsupported term error")
+	}
+}
+
+func TestMatchLegality(t *testing.T) {
+	card := scryfall.Card{
+		Name: "Sol Ring",
+		Legalities: scryfall.Legalities{
+			scryfall.FormatVintage:   scryfall.LegalityRestricted,
+			scryfall.FormatCommander: scryfall.LegalityLegal,
+			scryfall.FormatModern:    scryfall.LegalityBanned,
+			"somenewformat":          scryfall.LegalityLegal,
+		},
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"f:vintage", true},
+		{"restricted:vintage", true},
+		{"f:edh", true},
+		{"f:modern", false},
+		{"banned:modern", true},
+		{"f:somenewformat", true},
+		{"f:SomeNewFormat", true},
+		{"f:historic", false},
+	}
+	for _, test := range tests {
+		m, err := CompileString(test.query)
+		if err != nil {
+			t.Errorf("CompileString(%q): %v", test.query, err)
+			continue
+		}
+		if got := m.Match(card); got != test.want {
+			t.Errorf("%s matched %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestBuilderFormat(t *testing.T) {
+	got := New().Format(scryfall.FormatPauper, scryfall.LegalityNotLegal).Format("somenewformat", scryfall.LegalityLegal).String()
+	if want := "-f:pauper f:somenewformat"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMatchManaCost(t *testing.T) {
+	cards := []scryfall.Card{
+		{Name: "Ghor-Clan Rampager", ManaCost: "{2}{R}{G}"},
+		{Name: "Dryad Militant", ManaCost: "{G/W}{G/W}"},
+		{Name: "Atarka's Command", ManaCost: "{R}{G}"},
+		{Name: "Burning-Tree Emissary", ManaCost: "{R/G}{R/G}"},
+		{Name: "Ball Lightning", ManaCost: "{R}{R}{R}"},
+		{Name: "Fire // Ice", ManaCost: "{1}{R} // {1}{U}", CardFaces: []scryfall.CardFace{{Name: "Fire", ManaCost: "{1}{R}"}, {Name: "Ice", ManaCost: "{1}{U}"}}},
+		{Name: "Fling", ManaCost: "{1}{R}"},
+		{Name: "Simic Charm", ManaCost: "{G}{U}"},
+		{Name: "Treetop Village"},
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"m:rr", []string{"Ball Lightning"}},
+		{"m:{R}{R}", []string{"Ball Lightning"}},
+		{"m:rg", []string{"Ghor-Clan Rampager", "Atarka's Command"}},
+		{"m:{U}{G}", []string{"Simic Charm"}},
+		{"m:{G}{U}", []string{"Simic Charm"}},
+		{"m={G}{U}", []string{"Simic Charm"}},
+		{"m=gr", []string{"Atarka's Command"}},
+		{"m:{G/W}", []string{"Dryad Militant"}},
+		{"m:2", []string{"Ghor-Clan Rampager"}},
+		{"m:1r", []string{"Ghor-Clan Rampager", "Fire // Ice", "Fling"}},
+		{"m={1}{R}", []string{"Fire // Ice", "Fling"}},
+		{"m:u", []string{"Fire // Ice", "Simic Charm"}},
+	}
+	for _, test := range tests {
+		m, err := CompileString(test.query)
+		if err != nil {
+			t.Errorf("CompileString(%q): %v", test.query, err)
+			continue
+		}
+		got := []string{}
+		for _, card := range cards {
+			if m.Match(card) {
+				got = append(got, card.Name)
+			}
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s matched %v, want %v", test.query, got, test.want)
+		}
+	}
+}
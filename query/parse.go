@@ -0,0 +1,355 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var keywordAliases = map[string]string{
+	"color":      "c",
+	"colors":     "c",
+	"identity":   "id",
+	"ci":         "id",
+	"type":       "t",
+	"oracle":     "o",
+	"fulloracle": "fo",
+	"keyword":    "kw",
+	"mana":       "m",
+	"mv":         "cmc",
+	"manavalue":  "cmc",
+	"power":      "pow",
+	"toughness":  "tou",
+	"loyalty":    "loy",
+	"rarity":     "r",
+	"set":        "s",
+	"e":          "s",
+	"edition":    "s",
+	"number":     "cn",
+	"format":     "f",
+	"legal":      "f",
+	"artist":     "a",
+	"flavor":     "ft",
+	"watermark":  "wm",
+	"language":   "lang",
+}
+
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query: offset %d: %s", e.Offset, e.Msg)
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func Parse(src string) (Node, error) {
+	for i, r := range src {
+		if _, size := utf8.DecodeRuneInString(src[i:]); r == utf8.RuneError && size == 1 {
+			return nil, &SyntaxError{Offset: i, Msg: "invalid UTF-8"}
+		}
+	}
+	p := &parser{src: src}
+	p.skipSpace()
+	if p.eof() {
+		return nil, &SyntaxError{Offset: p.pos, Msg: "empty query"}
+	}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return nil, &SyntaxError{Offset: p.pos, Msg: fmt.Sprintf("unexpected %q", p.peek())}
+	}
+	return n, nil
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() rune {
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return r
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		p.pos += size
+	}
+}
+
+// word reports whether the case-insensitive word w starts at the current
+// position and stands alone.
+func (p *parser) word(w string) bool {
+	end := p.pos + len(w)
+	if end > len(p.src) || !strings.EqualFold(p.src[p.pos:end], w) {
+		return false
+	}
+	if end == len(p.src) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(p.src[end:])
+	return unicode.IsSpace(r) || r == '(' || r == ')'
+}
+
+func (p *parser) parseOr() (Node, error) {
+	start := p.pos
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{first}
+	for {
+		p.skipSpace()
+		if !p.word("or") {
+			break
+		}
+		p.pos += len("or")
+		p.skipSpace()
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, next)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return &OrExpr{Pos: Pos{Start: start, End: p.lastEnd(nodes)}, Nodes: nodes}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	start := p.pos
+	nodes := []Node{}
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek() == ')' || p.word("or") {
+			break
+		}
+		if p.word("and") {
+			if len(nodes) == 0 {
+				return nil, &SyntaxError{Offset: p.pos, Msg: `"and" without a left operand`}
+			}
+			p.pos += len("and")
+			p.skipSpace()
+			if p.eof() || p.peek() == ')' || p.word("or") {
+				return nil, &SyntaxError{Offset: p.pos, Msg: `"and" without a right operand`}
+			}
+			continue
+		}
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	switch len(nodes) {
+	case 0:
+		return nil, &SyntaxError{Offset: p.pos, Msg: "expected a search term"}
+	case 1:
+		return nodes[0], nil
+	}
+	return &AndExpr{Pos: Pos{Start: start, End: p.lastEnd(nodes)}, Nodes: nodes}, nil
+}
+
+func (p *parser) lastEnd(nodes []Node) int {
+	return nodes[len(nodes)-1].Position().End
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+		if p.eof() || unicode.IsSpace(p.peek()) {
+			return nil, &SyntaxError{Offset: start, Msg: "negation without a term"}
+		}
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{Pos: Pos{Start: start, End: x.Position().End}, X: x}, nil
+	}
+	if p.peek() == '(' {
+		p.pos++
+		p.skipSpace()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.eof() || p.peek() != ')' {
+			return nil, &SyntaxError{Offset: start, Msg: "unclosed parenthesis"}
+		}
+		p.pos++
+		setPos(x, Pos{Start: start, End: p.pos})
+		return x, nil
+	}
+	return p.parseTerm()
+}
+
+// setPos widens a parenthesized node to cover its parentheses, since the
+// AST has no separate group node.
+func setPos(n Node, pos Pos) {
+	switch n := n.(type) {
+	case *Term:
+		n.Pos = pos
+	case *NotExpr:
+		n.Pos = pos
+	case *AndExpr:
+		n.Pos = pos
+	case *OrExpr:
+		n.Pos = pos
+	}
+}
+
+func (p *parser) parseTerm() (Node, error) {
+	start := p.pos
+	term := &Term{}
+	if p.peek() == '!' {
+		term.Exact = true
+		p.pos++
+	}
+	if !term.Exact {
+		keyword, op, ok := p.scanKeyword()
+		if ok {
+			term.Keyword = keyword
+			term.Op = op
+			if !p.eof() && p.peek() == '/' {
+				value, err := p.scanRegex()
+				if err != nil {
+					return nil, err
+				}
+				term.Value = value
+				term.Regex = true
+				term.Pos = Pos{Start: start, End: p.pos}
+				return term, nil
+			}
+		}
+	}
+	value, err := p.scanValue()
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 && len(term.Keyword) == 0 {
+		return nil, &SyntaxError{Offset: start, Msg: "expected a search term"}
+	}
+	term.Value = value
+	term.Pos = Pos{Start: start, End: p.pos}
+	return term, nil
+}
+
+func (p *parser) scanKeyword() (string, Op, bool) {
+	i := p.pos
+	for i < len(p.src) && (isLetter(p.src[i]) || (i > p.pos && isDigit(p.src[i]))) {
+		i++
+	}
+	if i == p.pos || i == len(p.src) {
+		return "", "", false
+	}
+	var op Op
+	rest := p.src[i:]
+	switch {
+	case strings.HasPrefix(rest, "!="):
+		op = NE
+	case strings.HasPrefix(rest, "<="):
+		op = LTE
+	case strings.HasPrefix(rest, ">="):
+		op = GTE
+	case strings.HasPrefix(rest, ":"):
+		op = Colon
+	case strings.HasPrefix(rest, "="):
+		op = EQ
+	case strings.HasPrefix(rest, "<"):
+		op = LT
+	case strings.HasPrefix(rest, ">"):
+		op = GT
+	default:
+		return "", "", false
+	}
+	keyword := strings.ToLower(p.src[p.pos:i])
+	if alias, ok := keywordAliases[keyword]; ok {
+		keyword = alias
+	}
+	p.pos = i + len(op)
+	return keyword, op, true
+}
+
+func (p *parser) scanValue() (string, error) {
+	if p.eof() {
+		return "", nil
+	}
+	if q := p.peek(); q == '"' || q == '\'' {
+		return p.scanQuoted(byte(q))
+	}
+	start := p.pos
+	for !p.eof() {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if unicode.IsSpace(r) || r == ')' || r == '(' {
+			break
+		}
+		p.pos += size
+	}
+	return p.src[start:p.pos], nil
+}
+
+func (p *parser) scanQuoted(quote byte) (string, error) {
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.src) && (p.src[p.pos+1] == quote || p.src[p.pos+1] == '\\'):
+			b.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", &SyntaxError{Offset: start, Msg: "unterminated quoted string"}
+}
+
+func (p *parser) scanRegex() (string, error) {
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '/':
+			b.WriteByte('/')
+			p.pos += 2
+		case c == '/':
+			p.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", &SyntaxError{Offset: start, Msg: "unterminated regular expression"}
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
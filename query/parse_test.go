@@ -0,0 +1,104 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePrintRoundTrip(t *testing.T) {
+	tests := []string{
+		"t:goblin",
+		"(t:goblin)",
+		"-(a or b) c",
+		"c>=rg cmc<3 -t:land",
+		`o:"draw a card" or o:'it\'s'`,
+		`!"Lightning Bolt"`,
+		"name:/^bolt$/ (r:mythic or r:rare) f:modern",
+		"pow>-1 tou<=pow",
+		"a\u00a0b",
+		"t:\u3000elf",
+		"日本語 lang:ja",
+		`o:"back\\slash"`,
+	}
+	for _, src := range tests {
+		n, err := Parse(src)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", src, err)
+			continue
+		}
+		printed := Print(n)
+		again, err := Parse(printed)
+		if err != nil {
+			t.Errorf("Parse(Print(%q)) = Parse(%q): %v", src, printed, err)
+			continue
+		}
+		if reprinted := Print(again); reprinted != printed {
+			t.Errorf("round trip of %q: printed %q, then %q", src, printed, reprinted)
+		}
+	}
+}
+
+func TestParseGroupPosition(t *testing.T) {
+	n, err := Parse("(t:goblin)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := n.Position(), (Pos{Start: 0, End: 10}); got != want {
+		t.Errorf("(t:goblin) position = %+v, want %+v", got, want)
+	}
+
+	n, err = Parse("-(a or b) c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	and, ok := n.(*AndExpr)
+	if !ok {
+		t.Fatalf("got %T, want *AndExpr", n)
+	}
+	if got, want := and.Nodes[0].Position(), (Pos{Start: 0, End: 9}); got != want {
+		t.Errorf("-(a or b) position = %+v, want %+v", got, want)
+	}
+	not := and.Nodes[0].(*NotExpr)
+	if got, want := not.X.Position(), (Pos{Start: 1, End: 9}); got != want {
+		t.Errorf("(a or b) position = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseInvalidUTF8(t *testing.T) {
+	tests := []struct {
+		src    string
+		offset int
+	}{
+		{"\xff", 0},
+		{"a\xffb", 1},
+		{"t:goblin \xfe", 9},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.src)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) error = %v, want *SyntaxError", test.src, err)
+			continue
+		}
+		if syntaxErr.Offset != test.offset {
+			t.Errorf("Parse(%q) offset = %d, want %d", test.src, syntaxErr.Offset, test.offset)
+		}
+	}
+}
+
+func TestParseUnicodeSpace(t *testing.T) {
+	_, err := Parse("\u3000")
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Parse(ideographic space) error = %v, want *SyntaxError", err)
+	}
+
+	n, err := Parse("a\u00a0b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	and, ok := n.(*AndExpr)
+	if !ok || len(and.Nodes) != 2 {
+		t.Fatalf("Parse(a NBSP b) = %s, want two terms", Print(n))
+	}
+}
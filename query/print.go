@@ -0,0 +1,133 @@
+package query
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	precOr = iota
+	precAnd
+	precNot
+)
+
+func Print(n Node) string {
+	var b strings.Builder
+	printNode(&b, n, precOr)
+	return b.String()
+}
+
+func printNode(b *strings.Builder, n Node, prec int) {
+	switch n := n.(type) {
+	case *Term:
+		printTerm(b, n)
+	case *NotExpr:
+		if isEmpty(n.X) {
+			return
+		}
+		b.WriteByte('-')
+		printNode(b, n.X, precNot)
+	case *AndExpr:
+		printGroup(b, n.Nodes, " ", precAnd, prec)
+	case *OrExpr:
+		printGroup(b, n.Nodes, " or ", precOr, prec)
+	}
+}
+
+// isEmpty reports whether n prints as nothing. Hand-built trees can hold
+// empty groups, which would otherwise print as "()" or "-()".
+func isEmpty(n Node) bool {
+	switch n := n.(type) {
+	case *NotExpr:
+		return isEmpty(n.X)
+	case *AndExpr:
+		return allEmpty(n.Nodes)
+	case *OrExpr:
+		return allEmpty(n.Nodes)
+	}
+	return n == nil
+}
+
+func allEmpty(nodes []Node) bool {
+	for _, n := range nodes {
+		if !isEmpty(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func printGroup(b *strings.Builder, nodes []Node, sep string, groupPrec int, prec int) {
+	nodes = slices.DeleteFunc(slices.Clone(nodes), isEmpty)
+	switch len(nodes) {
+	case 0:
+		return
+	case 1:
+		printNode(b, nodes[0], prec)
+		return
+	}
+	parens := prec > groupPrec
+	if parens {
+		b.WriteByte('(')
+	}
+	for i, child := range nodes {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		// Scryfall's precedence between implicit and and "or" is easy to
+		// misread, so nested groups are always parenthesized.
+		printNode(b, child, precNot)
+	}
+	if parens {
+		b.WriteByte(')')
+	}
+}
+
+func printTerm(b *strings.Builder, t *Term) {
+	if t.Exact {
+		b.WriteByte('!')
+	}
+	if len(t.Keyword) != 0 {
+		b.WriteString(t.Keyword)
+		b.WriteString(string(t.Op))
+	}
+	if t.Regex {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(t.Value, "/", `\/`))
+		b.WriteByte('/')
+		return
+	}
+	b.WriteString(quote(t.Value, len(t.Keyword) == 0))
+}
+
+func quote(s string, bare bool) string {
+	if len(s) != 0 && !needsQuotes(s, bare) {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	if strings.Contains(s, `"`) && !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// needsQuotes reports whether s would be misread if printed verbatim. Bare
+// words additionally collide with the boolean operators and prefixes.
+func needsQuotes(s string, bare bool) bool {
+	if bare {
+		switch strings.ToLower(s) {
+		case "or", "and":
+			return true
+		}
+		if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "!") {
+			return true
+		}
+	}
+	for _, r := range s {
+		switch r {
+		case ' ', '\t', '\n', '"', '\'', '(', ')', ':', '<', '>', '=', '/', '\\':
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package scryfall
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryRecoversFromServerErrors(t *testing.T) {
+	attempts := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			http.Error(w, "<html>bad gateway</html>", http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"object":"card","name":"Lightning Bolt"}`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}))
+
+	card, err := client.GetCard(context.Background(), "bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Name != "Lightning Bolt" || attempts.Load() != 3 {
+		t.Errorf("got %q after %d attempts", card.Name, attempts.Load())
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+
+	_, err := client.GetCard(context.Background(), "bolt")
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 2 {
+		t.Fatalf("error = %v, want a RetryError after 2 attempts", err)
+	}
+}
+
+func TestRetryDoesNotRetryClientErrors(t *testing.T) {
+	attempts := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"object":"error","status":404,"code":"not_found","details":"No card found."}`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	_, err := client.GetCard(context.Background(), "bolt")
+	if !errors.Is(err, ErrNotFound) || attempts.Load() != 1 {
+		t.Fatalf("error = %v after %d attempts, want ErrNotFound after 1", err, attempts.Load())
+	}
+}
+
+func TestRetryAfterIsCappedByMaxDelay(t *testing.T) {
+	attempts := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"object":"card","name":"Lightning Bolt"}`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.GetCard(ctx, "bolt")
+	if err != nil {
+		t.Fatalf("GetCard: %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	if got := policy.backoff(1, time.Hour); got != time.Second {
+		t.Errorf("backoff with Retry-After 1h = %v, want %v", got, time.Second)
+	}
+	if got := policy.backoff(1, 200*time.Millisecond); got != 200*time.Millisecond {
+		t.Errorf("backoff with Retry-After 200ms = %v, want 200ms", got)
+	}
+	for attempt := 1; attempt < 70; attempt++ {
+		if got := policy.backoff(attempt, 0); got < 0 || got > policy.MaxDelay {
+			t.Errorf("backoff(%d) = %v, want within [0, %v]", attempt, got, policy.MaxDelay)
+		}
+	}
+}
@@ -19,36 +19,31 @@ type Ruling struct {
 	Comment     string `json:"comment"`
 }
 
-func (c *Client) getRulings(ctx context.Context, url string) ([]Ruling, error) {
-	rulings := []Ruling{}
-	err := c.listGet(ctx, url, &rulings)
-	if err != nil {
-		return nil, err
-	}
-	return rulings, nil
+func (c *Client) getRulings(ctx context.Context, url string) (List[Ruling], error) {
+	return listGet[Ruling](ctx, c, url)
 }
 
-func (c *Client) GetRulingsByMultiverseID(ctx context.Context, multiverseID int) ([]Ruling, error) {
+func (c *Client) GetRulingsByMultiverseID(ctx context.Context, multiverseID int) (List[Ruling], error) {
 	rulingsURI := fmt.Sprintf("cards/multiverse/%d/rulings", multiverseID)
 	return c.getRulings(ctx, rulingsURI)
 }
 
-func (c *Client) GetRulingsByMTGOID(ctx context.Context, mtgoID int) ([]Ruling, error) {
+func (c *Client) GetRulingsByMTGOID(ctx context.Context, mtgoID int) (List[Ruling], error) {
 	rulingsURI := fmt.Sprintf("cards/mtgo/%d/rulings", mtgoID)
 	return c.getRulings(ctx, rulingsURI)
 }
 
-func (c *Client) GetRulingsByArenaID(ctx context.Context, arenaID int) ([]Ruling, error) {
+func (c *Client) GetRulingsByArenaID(ctx context.Context, arenaID int) (List[Ruling], error) {
 	rulingsURI := fmt.Sprintf("cards/arena/%d/rulings", arenaID)
 	return c.getRulings(ctx, rulingsURI)
 }
 
-func (c *Client) GetRulingsBySetCodeAndCollectorNumber(ctx context.Context, setCode string, collectorNumber int) ([]Ruling, error) {
+func (c *Client) GetRulingsBySetCodeAndCollectorNumber(ctx context.Context, setCode string, collectorNumber int) (List[Ruling], error) {
 	rulingsURI := fmt.Sprintf("cards/%s/%d/rulings", setCode, collectorNumber)
 	return c.getRulings(ctx, rulingsURI)
 }
 
-func (c *Client) GetRulings(ctx context.Context, id string) ([]Ruling, error) {
+func (c *Client) GetRulings(ctx context.Context, id string) (List[Ruling], error) {
 	rulingsURI := fmt.Sprintf("cards/%s/rulings", id)
 	return c.getRulings(ctx, rulingsURI)
 }
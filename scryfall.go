@@ -8,10 +8,13 @@ import (
 	"fmt"
 	"go.uber.org/ratelimit"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 const (
@@ -24,7 +27,18 @@ const (
 	timestampFormat     = "2006-01-02T15:04:05.999Z07:00"
 )
 
-var ErrMultipleSecrets = errors.New("multiple secrets configured")
+const (
+	maxErrorBodySize    = 64 << 10
+	maxErrorDetailsSize = 512
+)
+
+var (
+	ErrMultipleSecrets = errors.New("multiple secrets configured")
+	ErrNotFound        = errors.New("not found")
+	ErrBadRequest      = errors.New("bad request")
+	ErrRateLimited     = errors.New("rate limited")
+	ErrAmbiguousName   = errors.New("ambiguous card name")
+)
 
 type Color string
 
@@ -80,19 +94,82 @@ type Error struct {
 	Details  string   `json:"details"`
 	Type     *string  `json:"type"`
 	Warnings []string `json:"warnings"`
+	Method   string   `json:"-"`
+	URL      string   `json:"-"`
 }
 
 func (e *Error) Error() string {
-	return fmt.Sprintf("%s: %s", e.Code, e.Details)
+	if len(e.Method) == 0 && len(e.URL) == 0 {
+		return fmt.Sprintf("%s: %s", e.Code, e.Details)
+	}
+	return fmt.Sprintf("%s %s: %s: %s", e.Method, e.URL, e.Code, e.Details)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrBadRequest:
+		return e.Status == http.StatusBadRequest
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	case ErrAmbiguousName:
+		return e.Type != nil && *e.Type == "ambiguous"
+	}
+	return false
+}
+
+func newResponseError(req *http.Request, resp *http.Response) *Error {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	scryfallErr := &Error{}
+	err := json.Unmarshal(b, scryfallErr)
+	if err != nil || len(scryfallErr.Code) == 0 {
+		// Proxies and load balancers answer with HTML or empty bodies, so fall
+		// back to describing the HTTP status.
+		details := strings.TrimSpace(string(b))
+		if len(details) == 0 || !utf8.ValidString(details) || strings.HasPrefix(details, "<") {
+			details = http.StatusText(resp.StatusCode)
+		}
+		if len(details) > maxErrorDetailsSize {
+			details = strings.ToValidUTF8(details[:maxErrorDetailsSize], "")
+		}
+		scryfallErr = &Error{
+			Code:    statusCode(resp.StatusCode),
+			Details: details,
+		}
+	}
+	scryfallErr.Status = resp.StatusCode
+	scryfallErr.Method = req.Method
+	scryfallErr.URL = req.URL.String()
+	return scryfallErr
+}
+
+func statusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	}
+	return strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
 }
 
 type clientOptions struct {
-	baseURI      string
-	userAgent    string
-	clientSecret string
-	grantSecret  string
-	client       *http.Client
-	limiter      ratelimit.Limiter
+	baseURI               string
+	userAgent             string
+	clientSecret          string
+	grantSecret           string
+	client                *http.Client
+	downloadClient        *http.Client
+	limiter               ratelimit.Limiter
+	retryPolicy           RetryPolicy
+	cache                 Cache
+	cacheTTLs             map[string]time.Duration
+	staleWhileRevalidate  time.Duration
+	coalesce              bool
+	collectionConcurrency int
 }
 
 type ClientOption func(*clientOptions)
@@ -127,18 +204,76 @@ func WithHTTPClient(client *http.Client) ClientOption {
 	}
 }
 
+// WithDownloadHTTPClient sets the client used by DownloadBulkData. Bulk files
+// run to hundreds of megabytes, so it should not have a whole-request
+// timeout; cancel the download through its context instead. By default the
+// download client shares the transport of the API client without a timeout.
+func WithDownloadHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.downloadClient = client
+	}
+}
+
 func WithLimiter(limiter ratelimit.Limiter) ClientOption {
 	return func(o *clientOptions) {
 		o.limiter = limiter
 	}
 }
 
+func WithRetryPolicy(retryPolicy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retryPolicy = retryPolicy
+	}
+}
+
+func WithCache(cache Cache) ClientOption {
+	return func(o *clientOptions) {
+		o.cache = cache
+	}
+}
+
+func WithCacheTTL(prefix string, ttl time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.cacheTTLs[prefix] = ttl
+	}
+}
+
+// WithStaleWhileRevalidate sets how long past its TTL a cached response is
+// still served immediately while it is refreshed in the background. Older
+// entries are revalidated before returning, and are only served if the API
+// cannot be reached.
+func WithStaleWhileRevalidate(window time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.staleWhileRevalidate = window
+	}
+}
+
+func WithRequestCoalescing(coalesce bool) ClientOption {
+	return func(o *clientOptions) {
+		o.coalesce = coalesce
+	}
+}
+
+func WithCollectionConcurrency(concurrency int) ClientOption {
+	return func(o *clientOptions) {
+		o.collectionConcurrency = concurrency
+	}
+}
+
 type Client struct {
-	baseURI       *url.URL
-	userAgent     string
-	authorization string
-	client        *http.Client
-	limiter       ratelimit.Limiter
+	baseURI               *url.URL
+	userAgent             string
+	authorization         string
+	client                *http.Client
+	downloadClient        *http.Client
+	limiter               ratelimit.Limiter
+	retryPolicy           RetryPolicy
+	cache                 Cache
+	cacheTTLs             map[string]time.Duration
+	staleWhileRevalidate  time.Duration
+	revalidating          sync.Map
+	inflight              *inflightGroup
+	collectionConcurrency int
 }
 
 func NewClient(options ...ClientOption) (*Client, error) {
@@ -148,7 +283,11 @@ func NewClient(options ...ClientOption) (*Client, error) {
 		client: &http.Client{
 			Timeout: defaultTimeout,
 		},
-		limiter: ratelimit.New(defaultReqPerSecond),
+		limiter:               ratelimit.New(defaultReqPerSecond),
+		cacheTTLs:             defaultCacheTTLs(),
+		staleWhileRevalidate:  defaultStaleWhileRevalidate,
+		coalesce:              true,
+		collectionConcurrency: 1,
 	}
 	for _, option := range options {
 		option(co)
@@ -167,41 +306,107 @@ func NewClient(options ...ClientOption) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	downloadClient := co.downloadClient
+	if downloadClient == nil {
+		downloadClient = &http.Client{
+			Transport:     co.client.Transport,
+			CheckRedirect: co.client.CheckRedirect,
+			Jar:           co.client.Jar,
+		}
+	}
 	c := &Client{
-		baseURI:       baseURI,
-		userAgent:     co.userAgent,
-		authorization: authorization,
-		client:        co.client,
-		limiter:       co.limiter,
+		baseURI:               baseURI,
+		userAgent:             co.userAgent,
+		authorization:         authorization,
+		client:                co.client,
+		downloadClient:        downloadClient,
+		limiter:               co.limiter,
+		retryPolicy:           co.retryPolicy,
+		cache:                 co.cache,
+		cacheTTLs:             co.cacheTTLs,
+		staleWhileRevalidate:  co.staleWhileRevalidate,
+		collectionConcurrency: co.collectionConcurrency,
+	}
+	if co.coalesce {
+		c.inflight = &inflightGroup{
+			calls: map[string]*inflightCall{},
+		}
 	}
 	return c, nil
 }
-func (c *Client) doReq(ctx context.Context, req *http.Request, respBody interface{}) error {
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (c *Client) doReq(ctx context.Context, req *http.Request, respBody interface{}, retryable bool) error {
+	resp, err := c.do(ctx, req, retryable)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(resp.body, respBody)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, retryable bool) (*response, error) {
 	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Accept", "application/json")
 	if len(c.authorization) != 0 {
 		req.Header.Set("Authorization", c.authorization)
 	}
+	maxAttempts := 1
+	if retryable && c.retryPolicy.MaxAttempts > 1 {
+		maxAttempts = c.retryPolicy.MaxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		resp, retryAfter, temporary, err := c.doAttempt(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if !temporary || attempt >= maxAttempts || ctx.Err() != nil {
+			if attempt > 1 {
+				return nil, &RetryError{Attempts: attempt, Err: err}
+			}
+			return nil, err
+		}
+		timer := time.NewTimer(c.retryPolicy.backoff(attempt, retryAfter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, &RetryError{Attempts: attempt, Err: err}
+		case <-timer.C:
+		}
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (c *Client) doAttempt(ctx context.Context, req *http.Request) (*response, time.Duration, bool, error) {
 	reqWithContext := req.WithContext(ctx)
 	if c.limiter != nil {
 		c.limiter.Take()
 	}
 	resp, err := c.client.Do(reqWithContext)
 	if err != nil {
-		return err
+		return nil, 0, ctx.Err() == nil, err
 	}
 	defer resp.Body.Close()
-	decoder := json.NewDecoder(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		scryfallErr := &Error{}
-		err = decoder.Decode(scryfallErr)
-		if err != nil {
-			return err
-		}
-		return scryfallErr
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotModified {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		return nil, retryAfter, isRetryableStatus(resp.StatusCode), newResponseError(req, resp)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, ctx.Err() == nil, err
 	}
-	return decoder.Decode(respBody)
+	return &response{status: resp.StatusCode, header: resp.Header, body: body}, 0, false, nil
 }
+
 func (c *Client) get(ctx context.Context, relativeURI string, respBody interface{}) error {
 	absoluteURI, err := c.baseURI.Parse(relativeURI)
 	if err != nil {
@@ -211,7 +416,14 @@ func (c *Client) get(ctx context.Context, relativeURI string, respBody interface
 	if err != nil {
 		return err
 	}
-	return c.doReq(ctx, req, respBody)
+	if c.cache != nil {
+		return c.cachedReq(ctx, req, respBody)
+	}
+	resp, err := c.doShared(ctx, req)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(resp.body, respBody)
 }
 func (c *Client) post(ctx context.Context, relativeURI string, reqBody interface{}, respBody interface{}) error {
 	absoluteURI, err := c.baseURI.Parse(relativeURI)
@@ -231,22 +443,94 @@ func (c *Client) post(ctx context.Context, relativeURI string, reqBody interface
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	return c.doReq(ctx, req, respBody)
+	return c.doReq(ctx, req, respBody, relativeURI == collectionURI)
 }
 
-type listResponse struct {
-	Data       json.RawMessage `json:"data"`
-	HasMore    bool            `json:"has_more"`
-	NextPage   *string         `json:"next_page"`
-	TotalCards *int            `json:"total_cards"`
-	Warnings   []string        `json:"warnings"`
+// List holds every page of a list endpoint, so it carries no paging state
+// of its own.
+type List[T any] struct {
+	Data       []T      `json:"data"`
+	TotalCards *int     `json:"total_cards,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
 }
 
-func (c *Client) listGet(ctx context.Context, url string, v interface{}) error {
-	response := &listResponse{}
-	err := c.get(ctx, url, response)
-	if err != nil {
-		return err
+type listPage[T any] struct {
+	Data       []T      `json:"data"`
+	HasMore    bool     `json:"has_more"`
+	NextPage   *string  `json:"next_page"`
+	TotalCards *int     `json:"total_cards"`
+	Warnings   []string `json:"warnings"`
+}
+
+type ListIterator[T any] struct {
+	client     *Client
+	uri        string
+	totalCards *int
+	warnings   []string
+}
+
+func newListIterator[T any](c *Client, uri string) *ListIterator[T] {
+	return &ListIterator[T]{
+		client: c,
+		uri:    uri,
+	}
+}
+
+func (it *ListIterator[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		it.totalCards = nil
+		it.warnings = nil
+		uri := it.uri
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			page := listPage[T]{}
+			err := it.client.get(ctx, uri, &page)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			it.totalCards = page.TotalCards
+			it.warnings = append(it.warnings, page.Warnings...)
+			for _, item := range page.Data {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !page.HasMore || page.NextPage == nil {
+				return
+			}
+			uri = *page.NextPage
+		}
+	}
+}
+
+func (it *ListIterator[T]) TotalCards() int {
+	if it.totalCards == nil {
+		return 0
+	}
+	return *it.totalCards
+}
+
+func (it *ListIterator[T]) Warnings() []string {
+	return it.warnings
+}
+
+func listGet[T any](ctx context.Context, c *Client, uri string) (List[T], error) {
+	it := newListIterator[T](c, uri)
+	list := List[T]{
+		Data: []T{},
+	}
+	for item, err := range it.All(ctx) {
+		if err != nil {
+			return List[T]{}, err
+		}
+		list.Data = append(list.Data, item)
 	}
-	return json.Unmarshal(response.Data, v)
+	list.TotalCards = it.totalCards
+	list.Warnings = it.warnings
+	return list, nil
 }
@@ -0,0 +1,103 @@
+package scryfall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// pagedServer serves pages as consecutive pages of a list at path, each one
+// pointing at the next through next_page.
+func pagedServer(t *testing.T, path string, pages ...string) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		page := 0
+		if p := r.URL.Query().Get("page"); len(p) != 0 {
+			fmt.Sscan(p, &page)
+		}
+		if page >= len(pages) {
+			http.NotFound(w, r)
+			return
+		}
+		next := ""
+		if page+1 < len(pages) {
+			next = fmt.Sprintf(`,"has_more":true,"next_page":"%s%s?page=%d"`, server.URL, path, page+1)
+		}
+		fmt.Fprintf(w, `{"object":"list","data":[%s]%s,"warnings":["page %d"]}`, pages[page], next, page)
+	}))
+	return server
+}
+
+func TestListSetsFollowsNextPage(t *testing.T) {
+	server := pagedServer(t, "/sets",
+		`{"object":"set","code":"lea"},{"object":"set","code":"leb"}`,
+		`{"object":"set","code":"2ed"}`,
+	)
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	sets, err := client.ListSets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := []string{}
+	for _, set := range sets.Data {
+		codes = append(codes, set.Code)
+	}
+	if want := []string{"lea", "leb", "2ed"}; !slices.Equal(codes, want) {
+		t.Errorf("got sets %v, want %v", codes, want)
+	}
+	if want := []string{"page 0", "page 1"}; !slices.Equal(sets.Warnings, want) {
+		t.Errorf("got warnings %v, want %v", sets.Warnings, want)
+	}
+}
+
+func TestGetRulingsFollowsNextPage(t *testing.T) {
+	server := pagedServer(t, "/cards/bolt/rulings",
+		`{"object":"ruling","comment":"first"}`,
+		`{"object":"ruling","comment":"second"}`,
+		`{"object":"ruling","comment":"third"}`,
+	)
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	rulings, err := client.GetRulings(context.Background(), "bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments := []string{}
+	for _, ruling := range rulings.Data {
+		comments = append(comments, ruling.Comment)
+	}
+	if want := []string{"first", "second", "third"}; !slices.Equal(comments, want) {
+		t.Errorf("got rulings %v, want %v", comments, want)
+	}
+}
+
+func TestListSetsFailsOnLaterPage(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.URL.Query().Get("page")) != 0 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"object":"error","status":404,"code":"not_found","details":"gone"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"object":"list","data":[{"object":"set","code":"lea"}],"has_more":true,"next_page":"%s/sets?page=1"}`, server.URL)
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+
+	_, err := client.ListSets(context.Background())
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
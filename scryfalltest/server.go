@@ -0,0 +1,435 @@
+package scryfalltest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tencorvids/scryfall"
+	"github.com/tencorvids/scryfall/bulk"
+	"github.com/tencorvids/scryfall/query"
+	"go.uber.org/ratelimit"
+)
+
+const (
+	defaultPageSize       = 175
+	maxCollectionRequests = 75
+)
+
+type Fixtures struct {
+	Cards    []scryfall.Card
+	Sets     []scryfall.Set
+	Rulings  []scryfall.Ruling
+	Symbols  []scryfall.CardSymbol
+	Catalogs map[string][]string
+	BulkData []scryfall.BulkData
+}
+
+type Server struct {
+	*httptest.Server
+	fixtures    Fixtures
+	store       *bulk.LocalStore
+	pageSize    int
+	mu          sync.Mutex
+	rateLimited int
+	retryAfter  time.Duration
+	requests    int
+}
+
+type ServerOption func(*Server)
+
+func WithPageSize(pageSize int) ServerOption {
+	return func(s *Server) {
+		s.pageSize = pageSize
+	}
+}
+
+func NewServer(fixtures Fixtures, options ...ServerOption) *Server {
+	s := &Server{
+		fixtures: fixtures,
+		store:    bulk.NewLocalStore(),
+		pageSize: defaultPageSize,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	for _, card := range fixtures.Cards {
+		s.store.Add(card)
+	}
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+func (s *Server) NewClient(options ...scryfall.ClientOption) (*scryfall.Client, error) {
+	defaults := []scryfall.ClientOption{
+		scryfall.WithBaseURI(s.URL),
+		scryfall.WithHTTPClient(s.Server.Client()),
+		scryfall.WithLimiter(ratelimit.NewUnlimited()),
+	}
+	return scryfall.NewClient(append(defaults, options...)...)
+}
+
+func (s *Server) RateLimitNext(n int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimited = n
+	s.retryAfter = retryAfter
+}
+
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /cards/search", s.handleSearch)
+	mux.HandleFunc("GET /cards/named", s.handleNamed)
+	mux.HandleFunc("GET /cards/autocomplete", s.handleAutocomplete)
+	mux.HandleFunc("GET /cards/random", s.handleRandom)
+	mux.HandleFunc("POST /cards/collection", s.handleCollection)
+	mux.HandleFunc("GET /cards/{id}", s.handleCard)
+	mux.HandleFunc("GET /cards/{first}/{second}", s.handleCardPath)
+	mux.HandleFunc("GET /cards/{first}/{second}/{third}", s.handleCardPath)
+	mux.HandleFunc("GET /sets", s.handleSets)
+	mux.HandleFunc("GET /sets/{code}", s.handleSet)
+	mux.HandleFunc("GET /symbology", s.handleSymbology)
+	mux.HandleFunc("GET /symbology/parse-mana", s.handleParseMana)
+	mux.HandleFunc("GET /catalog/{name}", s.handleCatalog)
+	mux.HandleFunc("GET /bulk-data", s.handleBulkDataList)
+	mux.HandleFunc("GET /bulk-data/{id}", s.handleBulkData)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "The requested endpoint does not exist.")
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		limited := s.rateLimited > 0
+		if limited {
+			s.rateLimited--
+		}
+		retryAfter := s.retryAfter
+		s.mu.Unlock()
+		if limited {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+			writeError(w, http.StatusTooManyRequests, "rate_limited", "You are sending requests too quickly.")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+type errorBody struct {
+	Object string `json:"object"`
+	scryfall.Error
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string, details string) {
+	writeJSON(w, status, &errorBody{
+		Object: "error",
+		Error: scryfall.Error{
+			Status:  status,
+			Code:    code,
+			Details: details,
+		},
+	})
+}
+
+func writeLookupError(w http.ResponseWriter, err error) {
+	scryfallErr := &scryfall.Error{}
+	if errors.As(err, &scryfallErr) {
+		writeJSON(w, scryfallErr.Status, &errorBody{Object: "error", Error: *scryfallErr})
+		return
+	}
+	writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+}
+
+type listBody[T any] struct {
+	Object     string   `json:"object"`
+	Data       []T      `json:"data"`
+	HasMore    bool     `json:"has_more"`
+	NextPage   *string  `json:"next_page,omitempty"`
+	TotalCards *int     `json:"total_cards,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+func writeList[T any](w http.ResponseWriter, r *http.Request, pageSize int, items []T, totalCards bool) {
+	page := 1
+	if p := r.URL.Query().Get("page"); len(p) != 0 {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "bad_request", "The page parameter must be a positive integer.")
+			return
+		}
+		page = n
+	}
+	start := min((page-1)*pageSize, len(items))
+	end := min(start+pageSize, len(items))
+	body := &listBody[T]{
+		Object: "list",
+		Data:   items[start:end],
+	}
+	if end < len(items) {
+		values := r.URL.Query()
+		values.Set("page", strconv.Itoa(page+1))
+		next := (&url.URL{
+			Scheme:   "http",
+			Host:     r.Host,
+			Path:     r.URL.Path,
+			RawQuery: values.Encode(),
+		}).String()
+		body.HasMore = true
+		body.NextPage = &next
+	}
+	if totalCards {
+		total := len(items)
+		body.TotalCards = &total
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len(q) == 0 {
+		writeError(w, http.StatusBadRequest, "bad_request", "You didn’t enter anything to search for.")
+		return
+	}
+	matcher, err := query.CompileString(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	cards := matcher.Filter(s.fixtures.Cards)
+	if len(cards) == 0 {
+		writeError(w, http.StatusNotFound, "not_found", "Your query didn’t match any cards.")
+		return
+	}
+	writeList(w, r, s.pageSize, cards, true)
+}
+
+func (s *Server) handleNamed(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	opts := scryfall.GetCardByNameOptions{
+		Set: values.Get("set"),
+	}
+	var card scryfall.Card
+	var err error
+	switch {
+	case values.Has("exact"):
+		card, err = s.store.GetCardByName(r.Context(), values.Get("exact"), true, opts)
+	case values.Has("fuzzy"):
+		card, err = s.store.GetCardByName(r.Context(), values.Get("fuzzy"), false, opts)
+	default:
+		writeError(w, http.StatusBadRequest, "bad_request", "You must provide either an exact or fuzzy parameter.")
+		return
+	}
+	if err != nil {
+		writeLookupError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &card)
+}
+
+func (s *Server) handleAutocomplete(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("q"))
+	seen := map[string]bool{}
+	names := []string{}
+	for _, card := range s.fixtures.Cards {
+		if len(query) < 2 || seen[card.Name] || !strings.HasPrefix(strings.ToLower(card.Name), query) {
+			continue
+		}
+		seen[card.Name] = true
+		names = append(names, card.Name)
+	}
+	sort.Strings(names)
+	if len(names) > 20 {
+		names = names[:20]
+	}
+	writeJSON(w, http.StatusOK, &scryfall.Catalog{TotalValues: len(names), Data: names})
+}
+
+func (s *Server) handleRandom(w http.ResponseWriter, r *http.Request) {
+	if len(s.fixtures.Cards) == 0 {
+		writeError(w, http.StatusNotFound, "not_found", "No cards are available.")
+		return
+	}
+	card := s.fixtures.Cards[rand.IntN(len(s.fixtures.Cards))]
+	writeJSON(w, http.StatusOK, &card)
+}
+
+func (s *Server) handleCollection(w http.ResponseWriter, r *http.Request) {
+	request := scryfall.GetCardsByIdentifiersRequest{}
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "The request body is not valid JSON.")
+		return
+	}
+	if len(request.Identifiers) > maxCollectionRequests {
+		writeError(w, http.StatusBadRequest, "bad_request", fmt.Sprintf("Too many identifiers. You may only request up to %d cards at a time.", maxCollectionRequests))
+		return
+	}
+	response, err := s.store.GetCardsByIdentifiers(r.Context(), request.Identifiers)
+	if err != nil {
+		writeLookupError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &struct {
+		Object string `json:"object"`
+		scryfall.GetCardsByIdentifiersResponse
+	}{
+		Object:                        "list",
+		GetCardsByIdentifiersResponse: response,
+	})
+}
+
+func (s *Server) intIDLookup(kind string) func(ctx context.Context, id int) (scryfall.Card, error) {
+	switch kind {
+	case "multiverse":
+		return s.store.GetCardByMultiverseID
+	case "mtgo":
+		return s.store.GetCardByMTGOID
+	case "arena":
+		return s.store.GetCardByArenaID
+	case "tcgplayer":
+		return s.store.GetCardByTCGPlayerID
+	}
+	return nil
+}
+
+func (s *Server) handleCardPath(w http.ResponseWriter, r *http.Request) {
+	first, second, third := r.PathValue("first"), r.PathValue("second"), r.PathValue("third")
+	var card scryfall.Card
+	var err error
+	rulings := false
+	if lookup := s.intIDLookup(first); lookup != nil {
+		id, convErr := strconv.Atoi(second)
+		if convErr != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", "The ID must be an integer.")
+			return
+		}
+		switch third {
+		case "":
+		case "rulings":
+			rulings = true
+		default:
+			writeError(w, http.StatusNotFound, "not_found", "The requested endpoint does not exist.")
+			return
+		}
+		card, err = lookup(r.Context(), id)
+	} else if len(third) == 0 && second == "rulings" {
+		rulings = true
+		card, err = s.store.GetCard(r.Context(), first)
+	} else {
+		lang := scryfall.LangEnglish
+		switch third {
+		case "":
+		case "rulings":
+			rulings = true
+		default:
+			lang = scryfall.Lang(third)
+		}
+		card, err = s.store.GetCardBySetCodeAndCollectorNumberInLang(r.Context(), first, second, lang)
+	}
+	if err != nil {
+		writeLookupError(w, err)
+		return
+	}
+	if rulings {
+		writeList(w, r, s.pageSize, s.rulings(card), false)
+		return
+	}
+	writeJSON(w, http.StatusOK, &card)
+}
+
+func (s *Server) handleCard(w http.ResponseWriter, r *http.Request) {
+	card, err := s.store.GetCard(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeLookupError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &card)
+}
+
+func (s *Server) rulings(card scryfall.Card) []scryfall.Ruling {
+	rulings := []scryfall.Ruling{}
+	for _, ruling := range s.fixtures.Rulings {
+		if ruling.OracleID == card.OracleID {
+			rulings = append(rulings, ruling)
+		}
+	}
+	return rulings
+}
+
+func (s *Server) handleSets(w http.ResponseWriter, r *http.Request) {
+	writeList(w, r, s.pageSize, s.fixtures.Sets, false)
+}
+
+func (s *Server) handleSet(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	for _, set := range s.fixtures.Sets {
+		if strings.EqualFold(set.Code, code) || set.ID == code {
+			writeJSON(w, http.StatusOK, &set)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "not_found", "No Magic set found for the given code.")
+}
+
+func (s *Server) handleSymbology(w http.ResponseWriter, r *http.Request) {
+	writeList(w, r, s.pageSize, s.fixtures.Symbols, false)
+}
+
+func (s *Server) handleParseMana(w http.ResponseWriter, r *http.Request) {
+	manaCost, err := scryfall.ParseManaCostOffline(r.URL.Query().Get("cost"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, &manaCost)
+}
+
+func (s *Server) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.fixtures.Catalogs[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "No catalog found with the given name.")
+		return
+	}
+	catalog := &scryfall.Catalog{
+		URI:         "http://" + r.Host + r.URL.Path,
+		TotalValues: len(data),
+		Data:        data,
+	}
+	writeJSON(w, http.StatusOK, catalog)
+}
+
+func (s *Server) handleBulkDataList(w http.ResponseWriter, r *http.Request) {
+	writeList(w, r, s.pageSize, s.fixtures.BulkData, false)
+}
+
+func (s *Server) handleBulkData(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, bulkData := range s.fixtures.BulkData {
+		if bulkData.ID == id || string(bulkData.Type) == id {
+			writeJSON(w, http.StatusOK, &bulkData)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "not_found", "No bulk data found with the given ID or type.")
+}
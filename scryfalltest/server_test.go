@@ -0,0 +1,159 @@
+package scryfalltest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tencorvids/scryfall"
+	"github.com/tencorvids/scryfall/scryfalltest"
+)
+
+func fixtureCards(n int) []scryfall.Card {
+	cards := []scryfall.Card{}
+	for i := range n {
+		colors := []scryfall.Color{"R"}
+		if i%3 == 0 {
+			colors = []scryfall.Color{}
+		}
+		cards = append(cards, scryfall.Card{
+			ID:              fmt.Sprintf("id-%03d", i),
+			OracleID:        fmt.Sprintf("oracle-%03d", i),
+			Name:            fmt.Sprintf("Card %03d", i),
+			Set:             "tst",
+			CollectorNumber: fmt.Sprint(i + 1),
+			Lang:            scryfall.LangEnglish,
+			TypeLine:        "Creature — Goblin",
+			Colors:          colors,
+		})
+	}
+	return cards
+}
+
+func newClient(t *testing.T, server *scryfalltest.Server, options ...scryfall.ClientOption) *scryfall.Client {
+	client, err := server.NewClient(options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestSearchCardsAllPaginates(t *testing.T) {
+	server := scryfalltest.NewServer(scryfalltest.Fixtures{Cards: fixtureCards(12)}, scryfalltest.WithPageSize(5))
+	defer server.Close()
+	client := newClient(t, server)
+
+	search, err := client.SearchCardsAll("t:goblin", scryfall.SearchCardsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for card, err := range search.All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, card.Name)
+	}
+	if len(names) != 12 || search.TotalCards() != 12 {
+		t.Errorf("got %d cards, total %d, want 12", len(names), search.TotalCards())
+	}
+	if got := server.Requests(); got != 3 {
+		t.Errorf("sent %d requests, want 3 pages", got)
+	}
+}
+
+func TestSearchColorless(t *testing.T) {
+	server := scryfalltest.NewServer(scryfalltest.Fixtures{Cards: fixtureCards(9)})
+	defer server.Close()
+	client := newClient(t, server)
+
+	result, err := client.SearchCards(context.Background(), "c:c", scryfall.SearchCardsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Cards) != 3 {
+		t.Errorf("c:c matched %d cards, want 3", len(result.Cards))
+	}
+	for _, card := range result.Cards {
+		if len(card.Colors) != 0 {
+			t.Errorf("c:c matched %s with colors %v", card.Name, card.Colors)
+		}
+	}
+}
+
+func TestSearchInvalidQuery(t *testing.T) {
+	server := scryfalltest.NewServer(scryfalltest.Fixtures{Cards: fixtureCards(1)})
+	defer server.Close()
+	client := newClient(t, server)
+
+	for _, q := range []string{"a\xffb", "\u3000", "(t:goblin"} {
+		_, err := client.SearchCards(context.Background(), q, scryfall.SearchCardsOptions{})
+		if !errors.Is(err, scryfall.ErrBadRequest) {
+			t.Errorf("SearchCards(%q) error = %v, want ErrBadRequest", q, err)
+		}
+	}
+}
+
+func TestGetCardsByIdentifiersBatches(t *testing.T) {
+	cards := fixtureCards(200)
+	server := scryfalltest.NewServer(scryfalltest.Fixtures{Cards: cards})
+	defer server.Close()
+	client := newClient(t, server, scryfall.WithCollectionConcurrency(3))
+
+	identifiers := []scryfall.CardIdentifier{}
+	for i := range 180 {
+		if i%10 == 0 {
+			identifiers = append(identifiers, scryfall.NewCardIdentifierByName(fmt.Sprintf("Missing %d", i)))
+			continue
+		}
+		identifiers = append(identifiers, scryfall.NewCardIdentifierByID(cards[i].ID))
+	}
+	response, err := client.GetCardsByIdentifiers(context.Background(), identifiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data) != 162 || len(response.NotFound) != 18 {
+		t.Fatalf("got %d cards and %d not found, want 162 and 18", len(response.Data), len(response.NotFound))
+	}
+	for i, card := range response.Data {
+		if want := identifiers[response.DataIndices[i]].ID; card.ID != want {
+			t.Errorf("card %d is %s, mapped to identifier for %s", i, card.ID, want)
+		}
+	}
+	for i, identifier := range response.NotFound {
+		if want := identifiers[response.NotFoundIndices[i]]; identifier != want {
+			t.Errorf("not found %d is %+v, mapped to %+v", i, identifier, want)
+		}
+	}
+	if got := server.Requests(); got != 3 {
+		t.Errorf("sent %d requests, want 3 batches", got)
+	}
+}
+
+func TestRateLimitedRequestsAreRetried(t *testing.T) {
+	cards := fixtureCards(1)
+	server := scryfalltest.NewServer(scryfalltest.Fixtures{Cards: cards})
+	defer server.Close()
+	client := newClient(t, server, scryfall.WithRetryPolicy(scryfall.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+	}))
+
+	server.RateLimitNext(2, time.Hour)
+	card, err := client.GetCard(context.Background(), cards[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.ID != cards[0].ID || server.Requests() != 3 {
+		t.Errorf("got %s after %d requests, want %s after 3", card.ID, server.Requests(), cards[0].ID)
+	}
+
+	server.RateLimitNext(3, 0)
+	_, err = client.GetCard(context.Background(), cards[0].ID)
+	if !errors.Is(err, scryfall.ErrRateLimited) {
+		t.Errorf("error = %v, want ErrRateLimited", err)
+	}
+}
@@ -56,14 +56,8 @@ type Set struct {
 	SearchURI     string  `json:"search_uri"`
 }
 
-func (c *Client) ListSets(ctx context.Context) ([]Set, error) {
-	sets := []Set{}
-	err := c.listGet(ctx, "sets", &sets)
-	if err != nil {
-		return nil, err
-	}
-
-	return sets, nil
+func (c *Client) ListSets(ctx context.Context) (List[Set], error) {
+	return listGet[Set](ctx, c, "sets")
 }
 
 func (c *Client) GetSet(ctx context.Context, code string) (Set, error) {
@@ -7,20 +7,20 @@ import (
 )
 
 type CardSymbol struct {
-	Symbol               string      `json:"symbol"`
-	SVGURI               string      `json:"svg_uri"`
-	ManaValue            float64     `json:"mana_value"`
-	Hybrid               bool        `json:"hybrid"`
-	Phyrexian            bool        `json:"phyrexian"`
-	GathererAlternatives interface{} `json:"gatherer_alternatives"`
-	LooseVariant         *string     `json:"loose_variant"`
-	English              string      `json:"english"`
-	Transposable         bool        `json:"transposable"`
-	RepresentsMana       bool        `json:"represents_mana"`
-	CMC                  float64     `json:"cmc"`
-	AppearsInManaCosts   bool        `json:"appears_in_mana_costs"`
-	Funny                bool        `json:"funny"`
-	Colors               []Color     `json:"colors"`
+	Symbol               string   `json:"symbol"`
+	SVGURI               string   `json:"svg_uri"`
+	ManaValue            float64  `json:"mana_value"`
+	Hybrid               bool     `json:"hybrid"`
+	Phyrexian            bool     `json:"phyrexian"`
+	GathererAlternatives []string `json:"gatherer_alternatives"`
+	LooseVariant         *string  `json:"loose_variant"`
+	English              string   `json:"english"`
+	Transposable         bool     `json:"transposable"`
+	RepresentsMana       bool     `json:"represents_mana"`
+	CMC                  float64  `json:"cmc"`
+	AppearsInManaCosts   bool     `json:"appears_in_mana_costs"`
+	Funny                bool     `json:"funny"`
+	Colors               []Color  `json:"colors"`
 }
 
 type ManaCost struct {
@@ -32,13 +32,8 @@ type ManaCost struct {
 	Multicolored bool    `json:"multicolored"`
 }
 
-func (c *Client) ListCardSymbols(ctx context.Context) ([]CardSymbol, error) {
-	symbols := []CardSymbol{}
-	err := c.listGet(ctx, "symbology", &symbols)
-	if err != nil {
-		return nil, err
-	}
-	return symbols, nil
+func (c *Client) ListCardSymbols(ctx context.Context) (List[CardSymbol], error) {
+	return listGet[CardSymbol](ctx, c, "symbology")
 }
 
 func (c *Client) ParseManaCost(ctx context.Context, cost string) (ManaCost, error) {
@@ -0,0 +1,141 @@
+package scryfall
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// symbologySnapshot is a copy of the /symbology endpoint so that symbol
+// lookups work without a network round trip.
+//
+//go:embed symbology.json
+var symbologySnapshot []byte
+
+var defaultSymbology = sync.OnceValue(func() *Symbology {
+	symbology, err := ParseSymbology(symbologySnapshot)
+	if err != nil {
+		panic("scryfall: invalid embedded symbology snapshot: " + err.Error())
+	}
+	return symbology
+})
+
+type Symbology struct {
+	symbols    []CardSymbol
+	bySymbol   map[string]int
+	byLoose    map[string]int
+	byGatherer map[string]int
+}
+
+func NewSymbology(symbols []CardSymbol) *Symbology {
+	s := &Symbology{
+		symbols:    append([]CardSymbol{}, symbols...),
+		bySymbol:   make(map[string]int, len(symbols)),
+		byLoose:    make(map[string]int, len(symbols)),
+		byGatherer: make(map[string]int, len(symbols)),
+	}
+	for i, symbol := range s.symbols {
+		s.bySymbol[symbol.Symbol] = i
+		if symbol.LooseVariant != nil {
+			s.byLoose[*symbol.LooseVariant] = i
+		}
+		for _, alternative := range symbol.GathererAlternatives {
+			s.byGatherer[alternative] = i
+		}
+	}
+	return s
+}
+
+// DefaultSymbology returns the registry built from the snapshot embedded in
+// the library. It may lag behind the API; use FetchSymbology to refresh it.
+func DefaultSymbology() *Symbology {
+	return defaultSymbology()
+}
+
+func FetchSymbology(ctx context.Context, fetcher SymbolFetcher) (*Symbology, error) {
+	symbols, err := fetcher.ListCardSymbols(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewSymbology(symbols.Data), nil
+}
+
+func ParseSymbology(data []byte) (*Symbology, error) {
+	symbols := []CardSymbol{}
+	err := json.Unmarshal(data, &symbols)
+	if err != nil {
+		return nil, err
+	}
+	return NewSymbology(symbols), nil
+}
+
+func ReadSymbology(r io.Reader) (*Symbology, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSymbology(data)
+}
+
+func (s *Symbology) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.symbols)
+}
+
+func (s *Symbology) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(s.symbols, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+func (s *Symbology) Len() int {
+	return len(s.symbols)
+}
+
+func (s *Symbology) Symbols() []CardSymbol {
+	return append([]CardSymbol{}, s.symbols...)
+}
+
+func (s *Symbology) Lookup(symbol string) (CardSymbol, bool) {
+	return s.lookup(s.bySymbol, symbol)
+}
+
+func (s *Symbology) LookupLooseVariant(variant string) (CardSymbol, bool) {
+	return s.lookup(s.byLoose, variant)
+}
+
+func (s *Symbology) LookupGathererAlternative(alternative string) (CardSymbol, bool) {
+	return s.lookup(s.byGatherer, alternative)
+}
+
+func (s *Symbology) Colors(symbol string) ([]Color, bool) {
+	cardSymbol, ok := s.Lookup(symbol)
+	if !ok {
+		return nil, false
+	}
+	return cardSymbol.Colors, true
+}
+
+func (s *Symbology) ManaValue(symbol string) (float64, bool) {
+	cardSymbol, ok := s.Lookup(symbol)
+	if !ok {
+		return 0, false
+	}
+	return cardSymbol.ManaValue, true
+}
+
+func (s *Symbology) Tokenizer() *TextTokenizer {
+	return NewTextTokenizer(s.symbols)
+}
+
+func (s *Symbology) lookup(index map[string]int, key string) (CardSymbol, bool) {
+	i, ok := index[key]
+	if !ok {
+		return CardSymbol{}, false
+	}
+	return s.symbols[i], true
+}
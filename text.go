@@ -0,0 +1,196 @@
+package scryfall
+
+import (
+	"html"
+	"strings"
+)
+
+type TextSegment struct {
+	Text     string
+	IsSymbol bool
+	Symbol   *CardSymbol
+}
+
+type TextTokenizer struct {
+	symbols map[string]CardSymbol
+}
+
+func NewTextTokenizer(symbols []CardSymbol) *TextTokenizer {
+	t := &TextTokenizer{
+		symbols: make(map[string]CardSymbol, len(symbols)),
+	}
+	for _, symbol := range symbols {
+		t.symbols[symbol.Symbol] = symbol
+	}
+	return t
+}
+
+// Tokenize splits text into runs of plain text and {…} symbols. Symbols that
+// are missing from the table are still returned as symbols, without metadata.
+func (t *TextTokenizer) Tokenize(text string) []TextSegment {
+	segments := []TextSegment{}
+	var plain strings.Builder
+	flush := func() {
+		if plain.Len() != 0 {
+			segments = append(segments, TextSegment{Text: plain.String()})
+			plain.Reset()
+		}
+	}
+	for len(text) != 0 {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			plain.WriteString(text)
+			break
+		}
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			plain.WriteString(text)
+			break
+		}
+		end += start + 1
+		token := text[start:end]
+		if strings.ContainsAny(token[1:len(token)-1], "{ \n") {
+			plain.WriteString(text[:start+1])
+			text = text[start+1:]
+			continue
+		}
+		plain.WriteString(text[:start])
+		flush()
+		segment := TextSegment{
+			Text:     token,
+			IsSymbol: true,
+		}
+		if symbol, ok := t.symbols[token]; ok {
+			segment.Symbol = &symbol
+		}
+		segments = append(segments, segment)
+		text = text[end:]
+	}
+	flush()
+	return segments
+}
+
+type TextRenderer interface {
+	Render(segments []TextSegment) string
+}
+
+type PlainTextRenderer struct{}
+
+func (PlainTextRenderer) Render(segments []TextSegment) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		if segment.Symbol != nil && len(segment.Symbol.English) != 0 {
+			b.WriteString(segment.Symbol.English)
+			continue
+		}
+		b.WriteString(segment.Text)
+	}
+	return b.String()
+}
+
+type HTMLRenderer struct {
+	SymbolClass string
+}
+
+func (r HTMLRenderer) Render(segments []TextSegment) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		if segment.Symbol == nil || len(segment.Symbol.SVGURI) == 0 {
+			b.WriteString(strings.ReplaceAll(html.EscapeString(segment.Text), "\n", "<br>"))
+			continue
+		}
+		b.WriteString(`<img src="`)
+		b.WriteString(html.EscapeString(segment.Symbol.SVGURI))
+		b.WriteString(`" alt="`)
+		b.WriteString(html.EscapeString(segment.Text))
+		b.WriteString(`" title="`)
+		b.WriteString(html.EscapeString(segment.Symbol.English))
+		b.WriteString(`"`)
+		if len(r.SymbolClass) != 0 {
+			b.WriteString(` class="`)
+			b.WriteString(html.EscapeString(r.SymbolClass))
+			b.WriteString(`"`)
+		}
+		b.WriteString(`>`)
+	}
+	return b.String()
+}
+
+type MarkdownRenderer struct{}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`#`, `\#`,
+	`|`, `\|`,
+	"\n", "  \n",
+)
+
+func (MarkdownRenderer) Render(segments []TextSegment) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		if segment.Symbol == nil || len(segment.Symbol.SVGURI) == 0 {
+			b.WriteString(markdownEscaper.Replace(segment.Text))
+			continue
+		}
+		b.WriteString("![")
+		b.WriteString(markdownEscaper.Replace(segment.Text))
+		b.WriteString("](")
+		b.WriteString(segment.Symbol.SVGURI)
+		b.WriteString(` "`)
+		b.WriteString(strings.ReplaceAll(segment.Symbol.English, `"`, `\"`))
+		b.WriteString(`")`)
+	}
+	return b.String()
+}
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiWhite   = "\x1b[97m"
+	ansiBlue    = "\x1b[94m"
+	ansiBlack   = "\x1b[90m"
+	ansiRed     = "\x1b[91m"
+	ansiGreen   = "\x1b[92m"
+	ansiGold    = "\x1b[93m"
+	ansiDefault = "\x1b[1m"
+)
+
+var ansiColors = map[Color]string{
+	ColorWhite: ansiWhite,
+	ColorBlue:  ansiBlue,
+	ColorBlack: ansiBlack,
+	ColorRed:   ansiRed,
+	ColorGreen: ansiGreen,
+}
+
+type ANSIRenderer struct{}
+
+func (ANSIRenderer) Render(segments []TextSegment) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		if !segment.IsSymbol {
+			b.WriteString(segment.Text)
+			continue
+		}
+		code := ansiDefault
+		if segment.Symbol != nil {
+			switch len(segment.Symbol.Colors) {
+			case 0:
+			case 1:
+				code = ansiColors[segment.Symbol.Colors[0]]
+			default:
+				code = ansiGold
+			}
+		}
+		b.WriteString(code)
+		b.WriteString(segment.Text)
+		b.WriteString(ansiReset)
+	}
+	return b.String()
+}